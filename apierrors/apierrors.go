@@ -0,0 +1,197 @@
+// Package apierrors writes the error responses of the API. Every error has a
+// stable machine-readable code, a human-readable message, optional
+// per-field details and the ID of the request it belongs to. Clients that
+// accept application/problem+json get an RFC 7807 problem document instead
+// of the default envelope.
+package apierrors
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Code identifies the kind of error. Codes are part of the API contract:
+// clients can rely on them, while messages may change.
+type Code string
+
+const (
+	// Generic codes, one per status
+	CodeBadRequest           Code = "bad_request"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeConflict             Code = "conflict"
+	CodeGone                 Code = "gone"
+	CodePreconditionFailed   Code = "precondition_failed"
+	CodePayloadTooLarge      Code = "payload_too_large"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeUnprocessable        Code = "unprocessable"
+	CodeRateLimited          Code = "rate_limited"
+	CodeInternal             Code = "internal_error"
+
+	// Request body and parameter problems
+	CodeValidationFailed Code = "validation_failed"
+	CodeInvalidJSON      Code = "invalid_json"
+
+	// Authentication
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeInvalidToken       Code = "invalid_token"
+	CodeInvalidCode        Code = "invalid_code"
+	CodeAccountDisabled    Code = "account_disabled"
+	CodeAccountLocked      Code = "account_locked"
+	CodeEmailNotVerified   Code = "email_not_verified"
+
+	// Cars
+	CodeVersionConflict  Code = "version_conflict"
+	CodeDuplicateVehicle Code = "duplicate_vehicle"
+
+	// Idempotency keys
+	CodeIdempotencyKeyReused Code = "idempotency_key_reused"
+	CodeRequestInProgress    Code = "request_in_progress"
+)
+
+// statusCodes are the codes used when a handler gives only a status
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusGone:                  CodeGone,
+	http.StatusPreconditionFailed:    CodePreconditionFailed,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeUnprocessable,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+}
+
+// FieldError describes what is wrong with one field of the request
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"email must be a valid email address"`
+}
+
+// Error is an API error
+type Error struct {
+	Status    int          `json:"-"`
+	Code      Code         `json:"code" example:"not_found" enums:"bad_request,unauthorized,forbidden,not_found,method_not_allowed,conflict,gone,precondition_failed,payload_too_large,unsupported_media_type,unprocessable,rate_limited,internal_error,validation_failed,invalid_json,invalid_credentials,invalid_token,invalid_code,account_disabled,account_locked,email_not_verified,version_conflict,duplicate_vehicle,idempotency_key_reused,request_in_progress"`
+	Message   string       `json:"message" example:"Car not found"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty" example:"0f8fad5bd9cb469fa16570867728950e"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Response is the body of every error response
+type Response struct {
+	Error Error `json:"error"`
+}
+
+// Problem is an error as an RFC 7807 problem document, sent to clients that
+// accept application/problem+json
+type Problem struct {
+	Type      string       `json:"type" example:"urn:car-management:error:not_found"`
+	Title     string       `json:"title" example:"Not Found"`
+	Status    int          `json:"status" example:"404"`
+	Detail    string       `json:"detail" example:"Car not found"`
+	Instance  string       `json:"instance,omitempty" example:"/api/cars/42"`
+	Code      Code         `json:"code" example:"not_found"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// New returns an error with the given status, code and message
+func New(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Respond writes an error with the generic code for the status and aborts
+// the request
+func Respond(c *gin.Context, status int, message string) {
+	code, ok := statusCodes[status]
+	if !ok {
+		code = CodeInternal
+		if status < http.StatusInternalServerError {
+			code = CodeBadRequest
+		}
+	}
+	Write(c, New(status, code, message))
+}
+
+// RespondCode writes an error with a specific code and aborts the request
+func RespondCode(c *gin.Context, status int, code Code, message string) {
+	Write(c, New(status, code, message))
+}
+
+// Internal writes a 500 error with message, attaching err to the request
+// so that it is logged without being shown to the client
+func Internal(c *gin.Context, err error, message string) {
+	if err != nil {
+		_ = c.Error(err)
+	}
+	Respond(c, http.StatusInternalServerError, message)
+}
+
+// Invalid writes a validation error. A *models.FieldError becomes a
+// validation_failed error with the field in its details; other errors are
+// plain bad requests.
+func Invalid(c *gin.Context, err error) {
+	var fieldErr *models.FieldError
+	if errors.As(err, &fieldErr) {
+		Write(c, &Error{
+			Status:  http.StatusBadRequest,
+			Code:    CodeValidationFailed,
+			Message: fieldErr.Message,
+			Details: []FieldError{{Field: fieldErr.Field, Message: fieldErr.Message}},
+		})
+		return
+	}
+	Respond(c, http.StatusBadRequest, err.Error())
+}
+
+// Write sends the error, as a problem document if the client prefers one,
+// and aborts the request. The error is also recorded on the context for
+// the request log.
+func Write(c *gin.Context, e *Error) {
+	e.RequestID = c.GetString("request_id")
+	_ = c.Error(e).SetType(gin.ErrorTypePublic)
+
+	if acceptsProblem(c.GetHeader("Accept")) {
+		problem := Problem{
+			Type:      "urn:car-management:error:" + string(e.Code),
+			Title:     http.StatusText(e.Status),
+			Status:    e.Status,
+			Detail:    e.Message,
+			Instance:  c.Request.URL.Path,
+			Code:      e.Code,
+			Errors:    e.Details,
+			RequestID: e.RequestID,
+		}
+		c.Render(e.Status, problemRender{problem})
+		c.Abort()
+		return
+	}
+
+	c.AbortWithStatusJSON(e.Status, Response{Error: *e})
+}
+
+// acceptsProblem reports whether the Accept header asks for problem
+// documents
+func acceptsProblem(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), "application/problem+json") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package apierrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report fields by the names clients send them under
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	}
+}
+
+// Bind writes the error returned by binding the request, such as from
+// ShouldBindJSON. Validation failures list each invalid field; malformed
+// bodies are reported without echoing parser internals.
+func Bind(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		details := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			details = append(details, FieldError{Field: fe.Field(), Message: fieldMessage(fe)})
+		}
+		Write(c, &Error{
+			Status:  http.StatusBadRequest,
+			Code:    CodeValidationFailed,
+			Message: "Some fields are invalid",
+			Details: details,
+		})
+	case errors.As(err, &typeErr):
+		message := fmt.Sprintf("%s must be a %s", typeErr.Field, jsonType(typeErr.Type))
+		Write(c, &Error{
+			Status:  http.StatusBadRequest,
+			Code:    CodeValidationFailed,
+			Message: message,
+			Details: []FieldError{{Field: typeErr.Field, Message: message}},
+		})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		RespondCode(c, http.StatusBadRequest, CodeInvalidJSON, "Request body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		RespondCode(c, http.StatusBadRequest, CodeInvalidJSON, "Request body is required")
+	case errors.As(err, &maxBytesErr):
+		Respond(c, http.StatusRequestEntityTooLarge, "Request body too large")
+	default:
+		Respond(c, http.StatusBadRequest, "Invalid request body")
+	}
+}
+
+// fieldMessage describes a failed validation rule
+func fieldMessage(fe validator.FieldError) string {
+	field := fe.Field()
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at most %s characters", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must be %s characters", field, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return field + " is invalid"
+	}
+}
+
+// jsonType names a Go type the way a JSON client would think of it
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "whole number"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,20 @@
+package apierrors
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// problemRender renders a problem document with its own content type
+type problemRender struct {
+	problem Problem
+}
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.problem)
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/problem+json")
+}
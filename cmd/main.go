@@ -1,25 +1,39 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/akashkumar7902/car-management-backend/apierrors"
 	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/database"
 	_ "github.com/akashkumar7902/car-management-backend/docs" // Import generated docs
+	"github.com/akashkumar7902/car-management-backend/jobs"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/metrics"
+	"github.com/akashkumar7902/car-management-backend/middlewares"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
 	"github.com/akashkumar7902/car-management-backend/routes"
+	"github.com/akashkumar7902/car-management-backend/storage"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
 func Default() gin.HandlerFunc {
 	config := cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "If-Match", "If-None-Match", "Idempotency-Key", "X-Organization-ID", "X-Request-ID"},
+		ExposeHeaders:    []string{"ETag", "Link", "Idempotent-Replayed", "X-Request-ID", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}
@@ -45,37 +59,138 @@ func Default() gin.HandlerFunc {
 func main() {
 	cfg := config.LoadConfig()
 
-	cld := config.InitCloudinary(cfg)
-
-	// Initialize Gin
-	r := gin.Default()
-	r.Use(Default())
-
-	// Serve static files (uploads)
-	r.Static("/uploads", "./uploads")
+	// Structured logs; the standard log package writes through them too
+	logger := logging.New(cfg)
+	slog.SetDefault(logger)
 
 	// Initialize Database
-	dsn := "host=" + cfg.DBHost + " user=" + cfg.DBUser + " password=" + cfg.DBPassword + " dbname=" + cfg.DBName + " port=" + cfg.DBPort + " sslmode=allow TimeZone=Asia/Kolkata"
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := database.Open(cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// // Migrate models
-	// err = db.AutoMigrate(&models.User{}, &models.Car{})
-	// if err != nil {
-	// 	log.Fatal("Failed to migrate database:", err)
-	// }
+	// Schema changes are versioned SQL migrations in migrations/sql. They
+	// target PostgreSQL; SQLite schemas are created by database.Open.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if cfg.DBDriver == "sqlite" {
+			log.Fatal("Migrations only apply to PostgreSQL")
+		}
+		runMigrateCommand(db, os.Args[2:])
+		return
+	}
+	if cfg.AutoMigrate && cfg.DBDriver != "sqlite" {
+		migrateOnStartup(db)
+	}
+
+	repos, err := repositories.New(db)
+	if err != nil {
+		log.Fatal("Failed to initialize repositories:", err)
+	}
+
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize image storage:", err)
+	}
+	store = metrics.InstrumentStore(store)
+
+	if err := metrics.RegisterDB(db, cfg.DBDriver); err != nil {
+		log.Fatal("Failed to register database metrics:", err)
+	}
+
+	// Cancelled on SIGINT or SIGTERM, which stops the background jobs and
+	// starts a graceful shutdown of the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Permanently remove cars that have been in the trash too long
+	if cfg.TrashPurgeInterval > 0 {
+		purger := &jobs.CarPurger{
+			Cars:      repos.Cars,
+			Store:     store,
+			Retention: cfg.TrashRetention,
+			Interval:  cfg.TrashPurgeInterval,
+		}
+		go purger.Run(ctx)
+	}
+
+	// Forget idempotency keys once their replay window is over
+	if cfg.IdempotencyCleanInterval > 0 {
+		cleaner := &jobs.IdempotencyKeyCleaner{
+			DB:       db,
+			Interval: cfg.IdempotencyCleanInterval,
+		}
+		go cleaner.Run(ctx)
+	}
+
+	m, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
+
+	limiter, err := ratelimit.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize rate limiter:", err)
+	}
+
+	// Initialize Gin. Requests are logged as JSON tagged with their request
+	// ID, and panics are turned into the same error responses as everything
+	// else.
+	r := gin.New()
+	r.Use(middlewares.RequestID(), middlewares.Logger(logger), middlewares.Metrics(), middlewares.Recovery(), Default())
+	r.NoRoute(func(c *gin.Context) {
+		apierrors.Respond(c, http.StatusNotFound, "Route not found")
+	})
+
+	// Client IPs, which rate limits are keyed by, are only taken from
+	// X-Forwarded-For when the request comes through a trusted proxy
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
+	// Serve static files (uploads)
+	r.Static("/uploads", cfg.UploadDir)
 
 	// Initialize Routes
-	routes.AuthRoutes(r, db, cfg)
-	routes.CarRoutes(r, db, cfg, cld)
+	routes.AuthRoutes(r, db, repos, cfg, m, limiter)
+	routes.CarRoutes(r, db, repos, cfg, store, m, limiter)
+	routes.OrganizationRoutes(r, db, repos, cfg, m, limiter)
+	routes.PublicRoutes(r, repos, cfg, limiter)
+	routes.AdminRoutes(r, db, repos, cfg, store, limiter)
+	routes.MetricsRoutes(r, cfg)
+	routes.HealthRoutes(r, db, store)
 
 	// Swagger Documentation
 	r.GET("/api/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Start Server
-	if err := r.Run(":" + cfg.Port); err != nil && err != http.ErrServerClosed {
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server listening", "addr", srv.Addr)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		log.Fatalf("Failed to run server: %v", err)
+	case <-ctx.Done():
+	}
+
+	// Stop accepting connections and let in-flight requests, such as
+	// uploads, finish within the grace period
+	logger.Info("Shutting down", "grace_period", cfg.ShutdownGracePeriod.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGracePeriod)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Graceful shutdown failed", "error", err)
+		srv.Close()
 	}
+	logger.Info("Server stopped")
 }
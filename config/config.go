@@ -3,21 +3,79 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// RateLimit allows Requests requests per Per, with bursts of up to Requests.
+// A zero RateLimit does not limit anything.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
 type Config struct {
-	Port           string
-	DBHost         string
-	DBPort         string
-	DBUser         string
-	DBPassword     string
-	DBName         string
-	JWTSecret      string
-	CloudName      string
-	CloudAPIKey    string
-	CloudAPISecret string
+	Port                     string
+	DBDriver                 string
+	DBPath                   string
+	DBHost                   string
+	DBPort                   string
+	DBUser                   string
+	DBPassword               string
+	DBName                   string
+	AutoMigrate              bool
+	JWTSecret                string
+	AccessTokenTTL           time.Duration
+	RefreshTokenTTL          time.Duration
+	SessionMaxAge            time.Duration
+	AppURL                   string
+	MailDriver               string
+	MailFrom                 string
+	MailLogPath              string
+	SMTPHost                 string
+	SMTPPort                 string
+	SMTPUsername             string
+	SMTPPassword             string
+	PasswordResetTTL         time.Duration
+	EmailVerificationTTL     time.Duration
+	RequireEmailVerification bool
+	TOTPIssuer               string
+	TwoFactorChallengeTTL    time.Duration
+	StorageDriver            string
+	UploadDir                string
+	UploadBaseURL            string
+	CloudName                string
+	CloudAPIKey              string
+	CloudAPISecret           string
+	TrashRetention           time.Duration
+	TrashPurgeInterval       time.Duration
+	IdempotencyKeyTTL        time.Duration
+	IdempotencyCleanInterval time.Duration
+	OrgInvitationTTL         time.Duration
+	TrustedProxies           []string
+	RateLimitStore           string
+	LoginRateLimit           RateLimit
+	LoginAccountRateLimit    RateLimit
+	SignupRateLimit          RateLimit
+	AuthRateLimit            RateLimit
+	APIRateLimit             RateLimit
+	PublicRateLimit          RateLimit
+	LoginLockoutThreshold    int
+	LoginLockoutDuration     time.Duration
+	LoginLockoutMaxDuration  time.Duration
+	LoginFailureWindow       time.Duration
+	LogFormat                string
+	LogLevel                 string
+	MetricsEnabled           bool
+	MetricsToken             string
+	ReadHeaderTimeout        time.Duration
+	ReadTimeout              time.Duration
+	WriteTimeout             time.Duration
+	IdleTimeout              time.Duration
+	ShutdownGracePeriod      time.Duration
 }
 
 func LoadConfig() Config {
@@ -27,15 +85,150 @@ func LoadConfig() Config {
 	}
 
 	return Config{
-		Port:           os.Getenv("PORT"),
-		DBHost:         os.Getenv("DB_HOST"),
-		DBPort:         os.Getenv("DB_PORT"),
-		DBUser:         os.Getenv("DB_USER"),
-		DBPassword:     os.Getenv("DB_PASSWORD"),
-		DBName:         os.Getenv("DB_NAME"),
-		JWTSecret:      os.Getenv("JWT_SECRET"),
-		CloudName:      os.Getenv("CLOUD_NAME"),
-		CloudAPIKey:    os.Getenv("CLOUD_API_KEY"),
-		CloudAPISecret: os.Getenv("CLOUD_API_SECRET"),
+		Port:                     os.Getenv("PORT"),
+		DBDriver:                 getString("DB_DRIVER", "postgres"),
+		DBPath:                   getString("DB_PATH", "car_management.db"),
+		DBHost:                   os.Getenv("DB_HOST"),
+		DBPort:                   os.Getenv("DB_PORT"),
+		DBUser:                   os.Getenv("DB_USER"),
+		DBPassword:               os.Getenv("DB_PASSWORD"),
+		DBName:                   os.Getenv("DB_NAME"),
+		AutoMigrate:              getBool("AUTO_MIGRATE", false),
+		JWTSecret:                os.Getenv("JWT_SECRET"),
+		AccessTokenTTL:           getDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:          getDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		SessionMaxAge:            getDuration("SESSION_MAX_AGE", 90*24*time.Hour),
+		AppURL:                   os.Getenv("APP_URL"),
+		MailDriver:               os.Getenv("MAIL_DRIVER"),
+		MailFrom:                 os.Getenv("MAIL_FROM"),
+		MailLogPath:              os.Getenv("MAIL_LOG_PATH"),
+		SMTPHost:                 os.Getenv("SMTP_HOST"),
+		SMTPPort:                 os.Getenv("SMTP_PORT"),
+		SMTPUsername:             os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:             os.Getenv("SMTP_PASSWORD"),
+		PasswordResetTTL:         getDuration("PASSWORD_RESET_TTL", time.Hour),
+		EmailVerificationTTL:     getDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		RequireEmailVerification: getBool("REQUIRE_EMAIL_VERIFICATION", false),
+		TOTPIssuer:               getString("TOTP_ISSUER", "Car Management"),
+		TwoFactorChallengeTTL:    getDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute),
+		StorageDriver:            getString("STORAGE_DRIVER", "cloudinary"),
+		UploadDir:                getString("UPLOAD_DIR", "./uploads"),
+		UploadBaseURL:            getString("UPLOAD_BASE_URL", "/uploads"),
+		CloudName:                os.Getenv("CLOUD_NAME"),
+		CloudAPIKey:              os.Getenv("CLOUD_API_KEY"),
+		CloudAPISecret:           os.Getenv("CLOUD_API_SECRET"),
+		TrashRetention:           getDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval:       getDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		IdempotencyKeyTTL:        getDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		IdempotencyCleanInterval: getDuration("IDEMPOTENCY_CLEAN_INTERVAL", time.Hour),
+		OrgInvitationTTL:         getDuration("ORG_INVITATION_TTL", 7*24*time.Hour),
+		TrustedProxies:           getList("TRUSTED_PROXIES"),
+		RateLimitStore:           getString("RATE_LIMIT_STORE", "memory"),
+		LoginRateLimit:           getRateLimit("LOGIN_RATE_LIMIT", RateLimit{20, time.Minute}),
+		LoginAccountRateLimit:    getRateLimit("LOGIN_ACCOUNT_RATE_LIMIT", RateLimit{10, time.Minute}),
+		SignupRateLimit:          getRateLimit("SIGNUP_RATE_LIMIT", RateLimit{10, time.Hour}),
+		AuthRateLimit:            getRateLimit("AUTH_RATE_LIMIT", RateLimit{30, time.Minute}),
+		APIRateLimit:             getRateLimit("API_RATE_LIMIT", RateLimit{300, time.Minute}),
+		PublicRateLimit:          getRateLimit("PUBLIC_RATE_LIMIT", RateLimit{120, time.Minute}),
+		LoginLockoutThreshold:    getInt("LOGIN_LOCKOUT_THRESHOLD", 5),
+		LoginLockoutDuration:     getDuration("LOGIN_LOCKOUT_DURATION", time.Minute),
+		LoginLockoutMaxDuration:  getDuration("LOGIN_LOCKOUT_MAX_DURATION", time.Hour),
+		LoginFailureWindow:       getDuration("LOGIN_FAILURE_WINDOW", 24*time.Hour),
+		LogFormat:                getString("LOG_FORMAT", "json"),
+		LogLevel:                 getString("LOG_LEVEL", "info"),
+		MetricsEnabled:           getBool("METRICS_ENABLED", true),
+		MetricsToken:             os.Getenv("METRICS_TOKEN"),
+		ReadHeaderTimeout:        getDuration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		ReadTimeout:              getDuration("HTTP_READ_TIMEOUT", time.Minute),
+		WriteTimeout:             getDuration("HTTP_WRITE_TIMEOUT", 2*time.Minute),
+		IdleTimeout:              getDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownGracePeriod:      getDuration("SHUTDOWN_GRACE_PERIOD", 30*time.Second),
+	}
+}
+
+// getString reads a string from the environment, falling back to def when
+// the variable is unset.
+func getString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// getDuration reads a Go duration string (e.g. "15m", "720h") from the
+// environment, falling back to def when the variable is unset or invalid.
+func getDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration for %s, using default %s", key, def)
+		return def
+	}
+	return d
+}
+
+// getBool reads a boolean ("true", "1", "false", ...) from the environment,
+// falling back to def when the variable is unset or invalid.
+func getBool(key string, def bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean for %s, using default %t", key, def)
+		return def
+	}
+	return b
+}
+
+// getInt reads an integer from the environment, falling back to def when
+// the variable is unset or invalid.
+func getInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer for %s, using default %d", key, def)
+		return def
+	}
+	return n
+}
+
+// getList reads a comma-separated list from the environment, dropping empty
+// entries. It returns nil when the variable is unset.
+func getList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getRateLimit reads a rate limit written as requests/duration (e.g.
+// "20/1m") from the environment, falling back to def when the variable is
+// unset or invalid. "off" disables the limit.
+func getRateLimit(key string, def RateLimit) RateLimit {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	if value == "off" {
+		return RateLimit{}
+	}
+	requests, per, _ := strings.Cut(value, "/")
+	n, err := strconv.Atoi(requests)
+	d, perErr := time.ParseDuration(per)
+	if err != nil || perErr != nil || n <= 0 || d <= 0 {
+		log.Printf("Invalid rate limit for %s, using default %d/%s", key, def.Requests, def.Per)
+		return def
 	}
+	return RateLimit{Requests: n, Per: d}
 }
@@ -0,0 +1,351 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/storage"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	Repos repositories.Repositories
+	Cfg   config.Config
+	Store storage.ImageStore
+}
+
+// AdminUser is the administrative view of a user account
+type AdminUser struct {
+	ID          uint       `json:"id"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Verified    bool       `json:"verified"`
+	TOTPEnabled bool       `json:"totp_enabled"`
+	DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newAdminUser(u models.User) AdminUser {
+	return AdminUser{
+		ID:          u.ID,
+		Username:    u.Username,
+		Email:       u.Email,
+		Role:        u.Role,
+		Verified:    u.Verified,
+		TOTPEnabled: u.TOTPEnabled,
+		DisabledAt:  u.DisabledAt,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description List and search user accounts (support and admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param q query string false "Search username or email"
+// @Param role query string false "Filter by role" Enums(user, support, admin)
+// @Param disabled query bool false "Filter by disabled state"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/admin/users [get]
+func (ac *AdminController) ListUsers(c *gin.Context) {
+	page, pageSize := parsePage(c)
+
+	opts := repositories.UserListOptions{Page: page, PageSize: pageSize, Query: c.Query("q")}
+	if role := c.Query("role"); role != "" {
+		if !models.ValidRole(role) {
+			apierrors.Respond(c, http.StatusBadRequest, "Invalid role")
+			return
+		}
+		opts.Role = role
+	}
+	if disabled := c.Query("disabled"); disabled != "" {
+		d, err := strconv.ParseBool(disabled)
+		if err != nil {
+			apierrors.Respond(c, http.StatusBadRequest, "Invalid disabled filter")
+			return
+		}
+		opts.Disabled = &d
+	}
+
+	users, total, err := ac.Repos.Users.List(c.Request.Context(), opts)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch users")
+		return
+	}
+
+	data := make([]AdminUser, 0, len(users))
+	for _, u := range users {
+		data = append(data, newAdminUser(u))
+	}
+
+	pagination := newPagination(page, pageSize, total)
+	setLinkHeader(c, pagination)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       data,
+		"pagination": pagination,
+	})
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Get any user account by ID (support and admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} AdminUser
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Router /api/admin/users/{id} [get]
+func (ac *AdminController) GetUser(c *gin.Context) {
+	user, ok := ac.loadUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminUser(user))
+}
+
+// DisableUser godoc
+// @Summary Disable a user
+// @Description Disable an account and revoke all of its sessions (support and admin only). Support staff cannot disable administrators.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} AdminUser
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/admin/users/{id}/disable [post]
+func (ac *AdminController) DisableUser(c *gin.Context) {
+	actor := c.MustGet("user").(models.User)
+
+	user, ok := ac.loadUser(c)
+	if !ok {
+		return
+	}
+
+	if user.ID == actor.ID {
+		apierrors.Respond(c, http.StatusBadRequest, "You cannot disable your own account")
+		return
+	}
+	if user.Role == models.RoleAdmin && actor.Role != models.RoleAdmin {
+		apierrors.Respond(c, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	if user.DisabledAt == nil {
+		if err := ac.Repos.Users.Disable(c.Request.Context(), &user); err != nil {
+			apierrors.Internal(c, err, "Failed to disable user")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, newAdminUser(user))
+}
+
+// EnableUser godoc
+// @Summary Enable a user
+// @Description Re-enable a disabled account (support and admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} AdminUser
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/admin/users/{id}/enable [post]
+func (ac *AdminController) EnableUser(c *gin.Context) {
+	user, ok := ac.loadUser(c)
+	if !ok {
+		return
+	}
+
+	if err := ac.Repos.Users.Update(c.Request.Context(), &user, map[string]interface{}{"disabled_at": nil}); err != nil {
+		apierrors.Internal(c, err, "Failed to enable user")
+		return
+	}
+	user.DisabledAt = nil
+
+	c.JSON(http.StatusOK, newAdminUser(user))
+}
+
+// UpdateUserRole godoc
+// @Summary Change a user's role
+// @Description Set the role of a user account (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param body body object true "New role (user, support or admin)"
+// @Success 200 {object} AdminUser
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/admin/users/{id}/role [put]
+func (ac *AdminController) UpdateUserRole(c *gin.Context) {
+	actor := c.MustGet("user").(models.User)
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+	if !models.ValidRole(input.Role) {
+		apierrors.Respond(c, http.StatusBadRequest, "Invalid role")
+		return
+	}
+
+	user, ok := ac.loadUser(c)
+	if !ok {
+		return
+	}
+
+	// Prevent an admin from locking everyone out by demoting themselves
+	if user.ID == actor.ID && input.Role != models.RoleAdmin {
+		apierrors.Respond(c, http.StatusBadRequest, "You cannot remove your own admin role")
+		return
+	}
+
+	if err := ac.Repos.Users.Update(c.Request.Context(), &user, map[string]interface{}{"role": input.Role}); err != nil {
+		apierrors.Internal(c, err, "Failed to update role")
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminUser(user))
+}
+
+// ListUserCars godoc
+// @Summary List a user's cars
+// @Description List every car owned by a user (support and admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} models.Car
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/admin/users/{id}/cars [get]
+func (ac *AdminController) ListUserCars(c *gin.Context) {
+	user, ok := ac.loadUser(c)
+	if !ok {
+		return
+	}
+
+	cars, err := ac.Repos.Cars.FindByOwner(c.Request.Context(), user.ID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch cars")
+		return
+	}
+
+	c.JSON(http.StatusOK, cars)
+}
+
+// DeleteCar godoc
+// @Summary Force-delete a car
+// @Description Delete any user's car (admin only). With permanent=true the row and its stored images are removed instead of moving the car to the trash.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param permanent query bool false "Hard delete"
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/admin/cars/{id} [delete]
+func (ac *AdminController) DeleteCar(c *gin.Context) {
+	permanent, _ := strconv.ParseBool(c.Query("permanent"))
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Car not found")
+		return
+	}
+
+	find := ac.Repos.Cars.FindByID
+	if permanent {
+		find = ac.Repos.Cars.FindByIDUnscoped
+	}
+
+	car, err := find(c.Request.Context(), id)
+	if err != nil {
+		apierrors.Respond(c, http.StatusNotFound, "Car not found")
+		return
+	}
+
+	before, after := car, car
+	err = recordCarChange(c, ac.Repos, models.RevisionDelete, &before, &after, func(cars repositories.CarRepository) error {
+		if !permanent {
+			return cars.Delete(c.Request.Context(), &after)
+		}
+		if err = cars.HardDelete(c.Request.Context(), &after); err != nil {
+			return err
+		}
+		after = after.Purged(time.Now())
+		return nil
+	})
+	if err != nil {
+		writeCarWriteError(c, err, "Failed to delete car")
+		return
+	}
+
+	if permanent {
+		if err := storage.DeleteURLs(c.Request.Context(), ac.Store, car.Images); err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to delete images", "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Car deleted successfully"})
+}
+
+// loadUser loads the user named by the :id parameter, writing the error
+// response if it does not exist
+func (ac *AdminController) loadUser(c *gin.Context) (models.User, bool) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "User not found")
+		return models.User{}, false
+	}
+
+	user, err := ac.Repos.Users.FindByID(c.Request.Context(), id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		apierrors.Respond(c, http.StatusNotFound, "User not found")
+		return models.User{}, false
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch user")
+		return models.User{}, false
+	}
+	return user, true
+}
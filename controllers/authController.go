@@ -3,23 +3,41 @@
 package controllers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/akashkumar7902/car-management-backend/apierrors"
 	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/metrics"
 	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
 	"github.com/akashkumar7902/car-management-backend/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// AuthController handles accounts and sessions. User records go through
+// Users; sessions and one-time tokens are plain GORM models on DB. Lockout,
+// if set, locks accounts out after repeated failed logins.
 type AuthController struct {
-	DB  *gorm.DB
-	Cfg config.Config
+	DB      *gorm.DB
+	Users   repositories.UserRepository
+	Cfg     config.Config
+	Mailer  mailer.Mailer
+	Lockout *ratelimit.Lockout
 }
 
+var errTokenAlreadyUsed = errors.New("token already used")
+
 // RegisterUser godoc
 // @Summary Register a new user
-// @Description Register a new user with username, email, and password
+// @Description Register a new user with username, email, and password. A verification link is emailed to the address.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -27,8 +45,9 @@ type AuthController struct {
 // @Param user body models.User true "User Info"
 //
 // @Success 201 {object} models.User
-// @Failure 400 {object} error
-// @Failure 500 {object} error
+// @Failure 400 {object} apierrors.Response
+// @Failure 429 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
 // @Router /api/users/signup [post]
 func (ac *AuthController) RegisterUser(c *gin.Context) {
 	var input struct {
@@ -38,14 +57,13 @@ func (ac *AuthController) RegisterUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierrors.Bind(c, err)
 		return
 	}
 
 	// Check if user already exists
-	var existingUser models.User
-	if err := ac.DB.Where("email = ?", input.Email).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User already exists"})
+	if _, err := ac.Users.FindByEmail(c.Request.Context(), input.Email); err == nil {
+		apierrors.Respond(c, http.StatusBadRequest, "User already exists")
 		return
 	}
 
@@ -54,36 +72,47 @@ func (ac *AuthController) RegisterUser(c *gin.Context) {
 		Username: input.Username,
 		Email:    input.Email,
 		Password: input.Password,
+		Role:     models.RoleUser,
 	}
 
 	if err := user.HashPassword(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		apierrors.Internal(c, err, "Failed to hash password")
 		return
 	}
 
-	if err := ac.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+	if err := ac.Users.Create(c.Request.Context(), &user); err != nil {
+		apierrors.Internal(c, err, "Failed to create user")
 		return
 	}
+	metrics.Signups.Inc()
+
+	// The account is usable right away; verification is only enforced
+	// where REQUIRE_EMAIL_VERIFICATION is enabled
+	if err := ac.sendVerificationEmail(c.Request.Context(), user); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to send verification email", "error", err)
+	}
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, ac.Cfg.JWTSecret)
+	// Generate tokens
+	token, refreshToken, err := ac.issueTokens(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		apierrors.Internal(c, err, "Failed to generate token")
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id":       user.ID,
-		"username": user.Username,
-		"email":    user.Email,
-		"token":    token,
+		"id":            user.ID,
+		"username":      user.Username,
+		"email":         user.Email,
+		"verified":      user.Verified,
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(ac.Cfg.AccessTokenTTL.Seconds()),
 	})
 }
 
 // LoginUser godoc
 // @Summary Login a user
-// @Description Authenticate user and return a JWT token
+// @Description Authenticate user and return a short-lived JWT access token and a refresh token. If two-factor authentication is enabled, a challenge token for /api/users/login/2fa is returned instead.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -91,9 +120,11 @@ func (ac *AuthController) RegisterUser(c *gin.Context) {
 // @Param user body models.User true "User Credentials"
 //
 // @Success 200 {object} models.User
-// @Failure 400 {object} error
-// @Failure 401 {object} error
-// @Failure 500 {object} error
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 429 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
 // @Router /api/users/login [post]
 func (ac *AuthController) LoginUser(c *gin.Context) {
 	var input struct {
@@ -102,34 +133,304 @@ func (ac *AuthController) LoginUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierrors.Bind(c, err)
 		return
 	}
 
-	// Find user by email
-	var user models.User
-	if err := ac.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+	// Find user by email. Unknown emails are locked out like accounts, so
+	// that locking does not reveal which accounts exist.
+	user, err := ac.Users.FindByEmail(c.Request.Context(), input.Email)
+	account := loginAccount(user.ID, input.Email)
+	if ac.loginLocked(c, account) {
+		metrics.Logins.WithLabelValues(metrics.LoginLocked).Inc()
+		return
+	}
+
+	if err != nil {
+		ac.loginFailed(c, account)
+		metrics.Logins.WithLabelValues(metrics.LoginFailure).Inc()
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidCredentials, "Invalid email or password")
 		return
 	}
 
 	// Check password
 	if !user.CheckPassword(input.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		ac.loginFailed(c, account)
+		metrics.Logins.WithLabelValues(metrics.LoginFailure).Inc()
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidCredentials, "Invalid email or password")
+		return
+	}
+
+	if user.IsDisabled() {
+		apierrors.RespondCode(c, http.StatusForbidden, apierrors.CodeAccountDisabled, "Account disabled")
+		return
+	}
+
+	// Accounts with two-factor enabled get a challenge instead of a session
+	if user.TOTPEnabled {
+		challengeToken, err := utils.GenerateChallengeToken(user.ID, ac.Cfg.JWTSecret, ac.Cfg.TwoFactorChallengeTTL)
+		if err != nil {
+			apierrors.Internal(c, err, "Failed to generate token")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"two_factor_required": true,
+			"challenge_token":     challengeToken,
+			"expires_in":          int(ac.Cfg.TwoFactorChallengeTTL.Seconds()),
+		})
+		return
+	}
+
+	// With two-factor enabled the failures are only forgotten once the
+	// second factor has been checked too
+	ac.loginSucceeded(c, account)
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+
+	// Generate tokens
+	token, refreshToken, err := ac.issueTokens(c, user)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to generate token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":            user.ID,
+		"username":      user.Username,
+		"email":         user.Email,
+		"verified":      user.Verified,
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(ac.Cfg.AccessTokenTTL.Seconds()),
+	})
+}
+
+// RefreshToken godoc
+// @Summary Refresh an access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated and the old one stops working. Refreshing never extends a session past SESSION_MAX_AGE (default 90 days) after login.
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "Refresh token"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/refresh [post]
+func (ac *AuthController) RefreshToken(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	tokenHash := utils.HashToken(input.RefreshToken)
+
+	var session models.Session
+	if err := ac.DB.Where("refresh_token_hash = ?", tokenHash).First(&session).Error; err != nil {
+		// A rotated-out token being presented again means it was copied;
+		// kill the whole session so neither party can keep using it.
+		var reused models.Session
+		if err := ac.DB.Where("previous_token_hash = ?", tokenHash).First(&reused).Error; err == nil {
+			ac.DB.Model(&reused).Update("revoked_at", time.Now())
+		}
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Invalid refresh token")
+		return
+	}
+
+	if !session.IsActive() {
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Session expired or revoked")
 		return
 	}
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, ac.Cfg.JWTSecret)
+	user, err := ac.Users.FindByID(c.Request.Context(), session.UserID)
+	if err != nil || user.IsDisabled() {
+		apierrors.Respond(c, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	refreshToken, err := utils.GenerateRandomToken()
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to generate token")
+		return
+	}
+
+	// Guard on the old hash so two concurrent refreshes cannot both succeed
+	result := ac.DB.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, tokenHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  utils.HashToken(refreshToken),
+			"previous_token_hash": tokenHash,
+			"expires_at":          session.RefreshedExpiry(ac.Cfg.RefreshTokenTTL),
+		})
+	if result.Error != nil {
+		apierrors.Internal(c, result.Error, "Failed to refresh session")
+		return
+	}
+	if result.RowsAffected == 0 {
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Invalid refresh token")
+		return
+	}
+
+	token, err := utils.GenerateToken(user.ID, session.ID, session.ActiveOrganizationID(), ac.Cfg.JWTSecret, ac.Cfg.AccessTokenTTL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		apierrors.Internal(c, err, "Failed to generate token")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"id":       user.ID,
-		"username": user.Username,
-		"email":    user.Email,
-		"token":    token,
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(ac.Cfg.AccessTokenTTL.Seconds()),
 	})
 }
+
+// LogoutUser godoc
+// @Summary Logout a user
+// @Description Revoke the current session, or every session of the user when "all" is true
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object false "Logout options"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/logout [post]
+func (ac *AuthController) LogoutUser(c *gin.Context) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	user := userInterface.(models.User)
+	session := c.MustGet("session").(models.Session)
+
+	var input struct {
+		All bool `json:"all"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			apierrors.Bind(c, err)
+			return
+		}
+	}
+
+	var err error
+	if input.All {
+		err = models.RevokeUserSessions(ac.DB, user.ID)
+	} else {
+		err = ac.DB.Model(&session).Update("revoked_at", time.Now()).Error
+	}
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to logout")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// issueTokens starts a new session for the user and returns the access and
+// refresh token pair for it
+func (ac *AuthController) issueTokens(c *gin.Context, user models.User) (string, string, error) {
+	refreshToken, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	session := models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		MaxExpiresAt:     time.Now().Add(ac.Cfg.SessionMaxAge),
+	}
+	session.ExpiresAt = session.RefreshedExpiry(ac.Cfg.RefreshTokenTTL)
+	if err := ac.DB.Create(&session).Error; err != nil {
+		return "", "", err
+	}
+
+	token, err := utils.GenerateToken(user.ID, session.ID, session.ActiveOrganizationID(), ac.Cfg.JWTSecret, ac.Cfg.AccessTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
+
+// issueUserToken creates a single-use token for the user and returns its
+// plaintext value, which is never stored
+func (ac *AuthController) issueUserToken(db *gorm.DB, userID uint, purpose string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	userToken := models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := db.Create(&userToken).Error; err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// loginAccount names what failed logins are counted against: the user if
+// the account exists, otherwise the email. The client IP is left out, so
+// that guesses spread over many addresses still lock the account.
+func loginAccount(userID uint, email string) string {
+	if userID != 0 {
+		return "user:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	return "email:" + models.NormalizeEmail(email)
+}
+
+// loginLocked reports whether the account is locked out after too many
+// failed logins, writing the 429 response if so
+func (ac *AuthController) loginLocked(c *gin.Context, account string) bool {
+	if ac.Lockout == nil {
+		return false
+	}
+	wait, err := ac.Lockout.Check(c.Request.Context(), "login:"+account)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to check login lockout", "error", err)
+		return false
+	}
+	if wait <= 0 {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	apierrors.RespondCode(c, http.StatusTooManyRequests, apierrors.CodeAccountLocked, "Too many failed login attempts, try again later")
+	return true
+}
+
+// loginFailed counts a failed login against the account
+func (ac *AuthController) loginFailed(c *gin.Context, account string) {
+	if ac.Lockout == nil {
+		return
+	}
+	if _, err := ac.Lockout.Fail(c.Request.Context(), "login:"+account); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to record login failure", "error", err)
+	}
+}
+
+// loginSucceeded forgets the account's failed logins
+func (ac *AuthController) loginSucceeded(c *gin.Context, account string) {
+	if ac.Lockout == nil {
+		return
+	}
+	if err := ac.Lockout.Succeed(c.Request.Context(), "login:"+account); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to reset login failures", "error", err)
+	}
+}
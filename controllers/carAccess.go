@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// carPermission is what a user may do with a car, from weakest to strongest
+type carPermission int
+
+const (
+	// viewCar allows reading the car and its history
+	viewCar carPermission = iota
+	// editCar allows changing the car's details and images
+	editCar
+	// ownCar allows deleting, restoring and sharing the car
+	ownCar
+)
+
+// loadCar loads the car named by the :id parameter and checks that the
+// authenticated user has the given permission on it, writing the error
+// response if not
+func (cc *CarController) loadCar(c *gin.Context, permission carPermission) (models.Car, bool) {
+	return cc.findCar(c, permission, cc.Repos.Cars.FindByID)
+}
+
+// findCar is loadCar with a custom lookup, e.g. one that also finds cars in
+// the trash
+func (cc *CarController) findCar(c *gin.Context, permission carPermission, find func(context.Context, uint) (models.Car, error)) (models.Car, bool) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+		return models.Car{}, false
+	}
+	user := userInterface.(models.User)
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Car not found")
+		return models.Car{}, false
+	}
+
+	car, err := find(c.Request.Context(), id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		apierrors.Respond(c, http.StatusNotFound, "Car not found")
+		return models.Car{}, false
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch car")
+		return models.Car{}, false
+	}
+
+	if !cc.authorizeCar(c, user, car, permission) {
+		return models.Car{}, false
+	}
+
+	return car, true
+}
+
+// authorizeCar checks that the user has the given permission on the car,
+// writing the error response if not. Fleet cars are only reachable with
+// their organization active: owners and managers can do anything and
+// drivers can view and edit. Personal cars are only reachable without an
+// active organization: their owner can do anything and other users need an
+// accepted share, which gives no access to cars in the trash.
+func (cc *CarController) authorizeCar(c *gin.Context, user models.User, car models.Car, permission carPermission) bool {
+	if member, ok := activeMembership(c); ok || car.OrganizationID != nil {
+		if !ok || car.OrganizationID == nil || *car.OrganizationID != member.OrganizationID {
+			apierrors.Respond(c, http.StatusForbidden, "Access denied")
+			return false
+		}
+		if permission == ownCar && !member.ManagesCars() {
+			apierrors.Respond(c, http.StatusForbidden, "Only organization owners and managers can do this")
+			return false
+		}
+		return true
+	}
+
+	if car.UserID == user.ID {
+		return true
+	}
+
+	share, err := cc.Repos.Shares.FindByCarAndUser(c.Request.Context(), car.ID, user.ID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		apierrors.Internal(c, err, "Failed to fetch car")
+		return false
+	}
+	if err != nil || !share.IsAccepted() || car.DeletedAt.Valid {
+		apierrors.Respond(c, http.StatusForbidden, "Access denied")
+		return false
+	}
+
+	switch {
+	case permission == ownCar:
+		apierrors.Respond(c, http.StatusForbidden, "Only the owner can do this")
+		return false
+	case permission == editCar && !share.CanEdit():
+		apierrors.Respond(c, http.StatusForbidden, "You have view-only access to this car")
+		return false
+	}
+	return true
+}
+
+// activeMembership returns the user's membership in the organization the
+// request acts in, if any
+func activeMembership(c *gin.Context) (models.OrganizationMember, bool) {
+	member, ok := c.Get("membership")
+	if !ok {
+		return models.OrganizationMember{}, false
+	}
+	return member.(models.OrganizationMember), true
+}
+
+// applyOrganization restricts car listings to the active organization's
+// fleet. Drivers cannot manage the fleet, so manage=true rejects them,
+// writing the error response.
+func applyOrganization(c *gin.Context, params *repositories.CarListOptions, manage bool) bool {
+	member, ok := activeMembership(c)
+	if !ok {
+		return true
+	}
+	if manage && !member.ManagesCars() {
+		apierrors.Respond(c, http.StatusForbidden, "Only organization owners and managers can do this")
+		return false
+	}
+	params.OrganizationID = member.OrganizationID
+	return true
+}
@@ -1,43 +1,68 @@
 package controllers
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/akashkumar7902/car-management-backend/apierrors"
 	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/mailer"
 	"github.com/akashkumar7902/car-management-backend/models"
-	"github.com/cloudinary/cloudinary-go/v2"
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/storage"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
+// maxUploadImages is how many images one request may upload
+const maxUploadImages = 10
+
 type CarController struct {
-	DB         *gorm.DB
-	Cfg        config.Config
-	Cloudinary *cloudinary.Cloudinary
+	Repos  repositories.Repositories
+	Cfg    config.Config
+	Store  storage.ImageStore
+	Mailer mailer.Mailer
 }
 
 // CreateCar handles creating a new car with optional image uploads
 // @Summary Create a new car
-// @Description Create a new car with title, description, tags, and optional images
+// @Description Create a new car with title, description, tags, vehicle details and optional images. While an organization is active the car joins its fleet; drivers cannot add cars.
 // @Tags Cars
 // @Accept multipart/form-data
 // @Produce json
 // @Param title formData string true "Title"
 // @Param description formData string false "Description"
 // @Param tags formData string false "Tags (comma-separated)"
+// @Param make formData string false "Make"
+// @Param model formData string false "Model"
+// @Param year formData int false "Model year"
+// @Param trim formData string false "Trim level"
+// @Param body_type formData string false "Body type" Enums(sedan, hatchback, suv, coupe, convertible, wagon, van, minivan, pickup, other)
+// @Param fuel_type formData string false "Fuel type" Enums(petrol, diesel, electric, hybrid, plugin_hybrid, cng, lpg, hydrogen, other)
+// @Param transmission formData string false "Transmission" Enums(manual, automatic, cvt, dct, other)
+// @Param mileage formData int false "Odometer reading"
+// @Param colour formData string false "Colour"
+// @Param registration_plate formData string false "Registration plate (unique per user)"
+// @Param vin formData string false "17-character VIN (check digit verified for North American VINs, unique per user)"
 // @Param images formData file false "Images" maxItems(10)
+// @Param X-Organization-ID header int false "Act in this organization instead of the token's; 0 for personal cars"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
 // @Success 201 {object} models.Car
-// @Failure 400 {object} error
-// @Failure 401 {object} error
-// @Failure 500 {object} error
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 422 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
 // @Router /api/cars [post]
 func (cc *CarController) CreateCar(c *gin.Context) {
 	userInterface, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 	user := userInterface.(models.User)
@@ -46,11 +71,6 @@ func (cc *CarController) CreateCar(c *gin.Context) {
 	description := c.PostForm("description")
 	tags := c.PostForm("tags")
 
-	if title == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
-		return
-	}
-
 	// Handle tags
 	tagList := []string{}
 	if tags != "" {
@@ -68,101 +88,143 @@ func (cc *CarController) CreateCar(c *gin.Context) {
 		Images:      []string{}, // Initialize as empty slice
 	}
 
+	// Cars added while an organization is active join its fleet
+	if member, ok := activeMembership(c); ok {
+		if !member.ManagesCars() {
+			apierrors.Respond(c, http.StatusForbidden, "Only organization owners and managers can add cars")
+			return
+		}
+		car.OrganizationID = &member.OrganizationID
+	}
+
+	if err := applyVehicleForm(c, &car); err != nil {
+		apierrors.Invalid(c, err)
+		return
+	}
+
+	car.Normalize()
+	if err := car.Validate(); err != nil {
+		apierrors.Invalid(c, err)
+		return
+	}
+
+	if msg, err := cc.vehicleConflict(c, car); err != nil {
+		apierrors.Internal(c, err, "Failed to create car")
+		return
+	} else if msg != "" {
+		apierrors.RespondCode(c, http.StatusConflict, apierrors.CodeDuplicateVehicle, msg)
+		return
+	}
+
 	// Handle image uploads
 	form, err := c.MultipartForm()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form data"})
+		apierrors.Respond(c, http.StatusBadRequest, "Invalid form data")
 		return
 	}
-
 	files := form.File["images"]
-	for _, file := range files {
-		// Upload to Cloudinary
-		uploadParams := uploader.UploadParams{
-			Folder: "car_management",
-		}
+	if len(files) > maxUploadImages {
+		apierrors.Respond(c, http.StatusBadRequest, fmt.Sprintf("Maximum %d images allowed", maxUploadImages))
+		return
+	}
 
-		uploadResult, err := cc.Cloudinary.Upload.Upload(c, file, uploadParams)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Image upload failed"})
-			return
-		}
-		car.Images = append(car.Images, uploadResult.SecureURL)
+	imageUrls, err := cc.uploadImages(c, files)
+	if err != nil {
+		apierrors.Internal(c, err, "Image upload failed")
+		return
 	}
+	car.Images = append(car.Images, imageUrls...)
 
-	if err := cc.DB.Create(&car).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create car"})
+	err = recordCarChange(c, cc.Repos, models.RevisionCreate, nil, &car, func(cars repositories.CarRepository) error {
+		return cars.Create(c.Request.Context(), &car)
+	})
+	if err != nil {
+		cc.deleteImages(c, imageUrls)
+		apierrors.Internal(c, err, "Failed to create car")
 		return
 	}
 
-	c.JSON(http.StatusCreated, car)
+	writeCar(c, http.StatusCreated, car)
 }
 
-// ListCars lists all cars of the logged-in user
-// @Summary List all cars
-// @Description Get a list of all cars for the logged-in user
+// ListCars lists the cars of the logged-in user
+// @Summary List cars
+// @Description Get a page of the logged-in user's cars, with optional sorting and filtering. Use scope to include cars shared with the user. While an organization is active, its fleet is listed instead. A Link header points to the neighbouring pages.
 // @Tags Cars
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Car
-// @Failure 401 {object} error
-// @Failure 500 {object} error
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param scope query string false "Cars to list: the user's own, those shared with them, or both" Enums(owned, shared, all) default(owned)
+// @Param sort query string false "Sort field" Enums(created_at, updated_at, title) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param tags_any query string false "Only cars with at least one of these tags (comma-separated)"
+// @Param tags_all query string false "Only cars with all of these tags (comma-separated)"
+// @Param created_after query string false "Only cars created at or after this RFC 3339 time or date"
+// @Param created_before query string false "Only cars created before this RFC 3339 time or date"
+// @Param X-Organization-ID header int false "Act in this organization instead of the token's; 0 for personal cars"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} CarPage
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
 // @Router /api/cars [get]
 func (cc *CarController) ListCars(c *gin.Context) {
 	userInterface, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 	user := userInterface.(models.User)
 
-	var cars []models.Car
-	if err := cc.DB.Where("user_id = ?", user.ID).Find(&cars).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cars"})
+	params, err := parseCarListParams(c, false)
+	if err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !applyOrganization(c, &params, false) {
+		return
+	}
+
+	cars, total, err := cc.Repos.Cars.ListForUser(c.Request.Context(), user.ID, params)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch cars")
 		return
 	}
 
-	c.JSON(http.StatusOK, cars)
+	pagination := newPagination(params.Page, params.PageSize, total)
+	setLinkHeader(c, pagination)
+
+	writeJSONIfModified(c, CarPage{Data: cars, Pagination: pagination})
 }
 
 // GetCar retrieves a specific car
 // @Summary Get a specific car
-// @Description Get car by ID for the logged-in user
+// @Description Get car by ID. The user must own the car or have accepted a share of it.
 // @Tags Cars
 // @Accept json
 // @Produce json
 // @Param id path int true "Car ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} models.Car
-// @Failure 401 {object} error
-// @Failure 403 {object} error
-// @Failure 404 {object} error
+// @Success 304 "Not modified"
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
 // @Router /api/cars/{id} [get]
 func (cc *CarController) GetCar(c *gin.Context) {
-	userInterface, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	car, ok := cc.loadCar(c, viewCar)
+	if !ok {
 		return
 	}
-	user := userInterface.(models.User)
 
-	carID := c.Param("id")
-	var car models.Car
-	if err := cc.DB.First(&car, carID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Car not found"})
-		return
-	}
-
-	if car.UserID != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
-		return
-	}
-
-	c.JSON(http.StatusOK, car)
+	writeCarIfModified(c, car)
 }
 
 // UpdateCar updates a specific car
 // @Summary Update a car
-// @Description Update car details for the logged-in user
+// @Description Update car details. The user must own the car or have accepted an editor share of it.
 // @Tags Cars
 // @Accept multipart/form-data
 // @Produce json
@@ -170,37 +232,42 @@ func (cc *CarController) GetCar(c *gin.Context) {
 // @Param title formData string false "Title"
 // @Param description formData string false "Description"
 // @Param tags formData string false "Tags (comma-separated)"
+// @Param make formData string false "Make"
+// @Param model formData string false "Model"
+// @Param year formData int false "Model year"
+// @Param trim formData string false "Trim level"
+// @Param body_type formData string false "Body type" Enums(sedan, hatchback, suv, coupe, convertible, wagon, van, minivan, pickup, other)
+// @Param fuel_type formData string false "Fuel type" Enums(petrol, diesel, electric, hybrid, plugin_hybrid, cng, lpg, hydrogen, other)
+// @Param transmission formData string false "Transmission" Enums(manual, automatic, cvt, dct, other)
+// @Param mileage formData int false "Odometer reading"
+// @Param colour formData string false "Colour"
+// @Param registration_plate formData string false "Registration plate (unique per user)"
+// @Param vin formData string false "17-character VIN (check digit verified for North American VINs, unique per user)"
 // @Param images formData string false "Images" maxItems(10)
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
 // @Success 200 {object} models.Car
-// @Failure 400 {object} error
-// @Failure 401 {object} error
-// @Failure 403 {object} error
-// @Failure 404 {object} error
-// @Failure 500 {object} error
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
 // @Router /api/cars/{id} [put]
 func (cc *CarController) UpdateCar(c *gin.Context) {
-	userInterface, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-	user := userInterface.(models.User)
-
-	carID := c.Param("id")
-	var car models.Car
-	if err := cc.DB.First(&car, carID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Car not found"})
+	car, ok := cc.loadCar(c, editCar)
+	if !ok {
 		return
 	}
-
-	if car.UserID != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	before := car
+	if !checkIfMatch(c, car) {
 		return
 	}
 
 	// Parse form data
 	if err := c.Request.ParseMultipartForm(32 << 20); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form data"})
+		apierrors.Respond(c, http.StatusBadRequest, "Failed to parse form data")
 		return
 	}
 
@@ -224,32 +291,37 @@ func (cc *CarController) UpdateCar(c *gin.Context) {
 		car.Tags = tags
 	}
 
-	// Handle image uploads
-	form := c.Request.MultipartForm
-	files := form.File["images"]
-	if len(files) > 10 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Maximum 10 images allowed"})
+	if err := applyVehicleForm(c, &car); err != nil {
+		apierrors.Invalid(c, err)
 		return
 	}
 
-	newImageUrls := []string{}
+	car.Normalize()
+	if err := car.Validate(); err != nil {
+		apierrors.Invalid(c, err)
+		return
+	}
 
-	for _, fileHeader := range files {
-		file, err := fileHeader.Open()
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open image"})
-			return
-		}
-		defer file.Close()
+	if msg, err := cc.vehicleConflict(c, car); err != nil {
+		apierrors.Internal(c, err, "Failed to update car")
+		return
+	} else if msg != "" {
+		apierrors.RespondCode(c, http.StatusConflict, apierrors.CodeDuplicateVehicle, msg)
+		return
+	}
 
-		// Upload to Cloudinary
-		uploadResult, err := cc.Cloudinary.Upload.Upload(c, file, uploader.UploadParams{})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
-			return
-		}
+	// Handle image uploads
+	form := c.Request.MultipartForm
+	files := form.File["images"]
+	if len(files) > maxUploadImages {
+		apierrors.Respond(c, http.StatusBadRequest, fmt.Sprintf("Maximum %d images allowed", maxUploadImages))
+		return
+	}
 
-		newImageUrls = append(newImageUrls, uploadResult.SecureURL)
+	newImageUrls, err := cc.uploadImages(c, files)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to upload image")
+		return
 	}
 
 	// Append new images to existing images
@@ -258,89 +330,229 @@ func (cc *CarController) UpdateCar(c *gin.Context) {
 	}
 
 	// Save updated car
-	if err := cc.DB.Save(&car).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update car"})
+	err = recordCarChange(c, cc.Repos, models.RevisionUpdate, &before, &car, func(cars repositories.CarRepository) error {
+		return cars.Save(c.Request.Context(), &car)
+	})
+	if err != nil {
+		cc.deleteImages(c, newImageUrls)
+		writeCarWriteError(c, err, "Failed to update car")
 		return
 	}
 
-	c.JSON(http.StatusOK, car)
+	writeCar(c, http.StatusOK, car)
 }
 
 // DeleteCar deletes a specific car
 // @Summary Delete a car
-// @Description Delete a car by ID for the logged-in user
+// @Description Move a car to the trash, where it can be restored until it is purged. With permanent=true the car and its stored images are removed immediately; this also works for cars already in the trash.
 // @Tags Cars
 // @Accept json
 // @Produce json
 // @Param id path int true "Car ID"
-// @Success 200 {object} error
-// @Failure 401 {object} error
-// @Failure 403 {object} error
-// @Failure 404 {object} error
+// @Param permanent query bool false "Delete permanently"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
 // @Router /api/cars/{id} [delete]
 func (cc *CarController) DeleteCar(c *gin.Context) {
-	userInterface, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
+	permanent, _ := strconv.ParseBool(c.Query("permanent"))
+
+	find := cc.Repos.Cars.FindByID
+	if permanent {
+		find = cc.Repos.Cars.FindByIDUnscoped
 	}
-	user := userInterface.(models.User)
 
-	carID := c.Param("id")
-	var car models.Car
-	if err := cc.DB.First(&car, carID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Car not found"})
+	car, ok := cc.findCar(c, ownCar, find)
+	if !ok {
 		return
 	}
-
-	if car.UserID != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	if !checkIfMatch(c, car) {
 		return
 	}
 
-	if err := cc.DB.Delete(&car).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete car"})
+	before, after := car, car
+	err := recordCarChange(c, cc.Repos, models.RevisionDelete, &before, &after, func(cars repositories.CarRepository) error {
+		if !permanent {
+			return cars.Delete(c.Request.Context(), &after)
+		}
+		if err := cars.HardDelete(c.Request.Context(), &after); err != nil {
+			return err
+		}
+		after = after.Purged(time.Now())
+		return nil
+	})
+	if err != nil {
+		writeCarWriteError(c, err, "Failed to delete car")
 		return
 	}
 
+	// Trashed cars keep their images so they can be restored; the purge job
+	// removes them once the retention period is over
+	if permanent {
+		cc.deleteImages(c, car.Images)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Car deleted successfully"})
 }
 
-// SearchCars searches cars based on a keyword
+// SearchCars searches cars using full-text search
 // @Summary Search cars
-// @Description Search cars by keyword in title, description, or tags
+// @Description Full-text search over title, tags, make, model, description, trim and colour, ranked by relevance.
+// @Description Terms are combined with AND; use "quoted phrases", prefix* matches, -excluded terms and OR.
+// @Description Highlights are HTML-escaped with matches wrapped in <mark> tags.
 // @Tags Cars
 // @Accept json
 // @Produce json
-// @Param keyword query string true "Search keyword"
-// @Success 200 {array} models.Car
-// @Failure 400 {object} error
-// @Failure 401 {object} error
-// @Failure 500 {object} error
+// @Param keyword query string true "Search query"
+// @Param scope query string false "Cars to search: the user's own, those shared with them, or both" Enums(owned, shared, all) default(owned)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(relevance, created_at, updated_at, title) default(relevance)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param tags_any query string false "Only cars with at least one of these tags (comma-separated)"
+// @Param tags_all query string false "Only cars with all of these tags (comma-separated)"
+// @Param created_after query string false "Only cars created at or after this RFC 3339 time or date"
+// @Param created_before query string false "Only cars created before this RFC 3339 time or date"
+// @Param X-Organization-ID header int false "Act in this organization instead of the token's; 0 for personal cars"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} CarSearchPage
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
 // @Router /api/cars/search [get]
 func (cc *CarController) SearchCars(c *gin.Context) {
-    userInterface, exists := c.Get("user")
-    if !exists {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-        return
-    }
-    user := userInterface.(models.User)
-
-    keyword := c.Query("keyword")
-    if keyword == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Keyword query parameter is required"})
-        return
-    }
-
-    var cars []models.Car
-    if err := cc.DB.Where(
-        "user_id = ? AND (title ILIKE ? OR description ILIKE ? OR ? = ANY(tags))",
-        user.ID, "%"+keyword+"%", "%"+keyword+"%", keyword,
-    ).Find(&cars).Error; err != nil {
-        log.Println(err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search cars"})
-        return
-    }
-
-    c.JSON(http.StatusOK, cars)
+	userInterface, exists := c.Get("user")
+	if !exists {
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	user := userInterface.(models.User)
+
+	keyword := c.Query("keyword")
+	if keyword == "" {
+		apierrors.Respond(c, http.StatusBadRequest, "Keyword query parameter is required")
+		return
+	}
+
+	params, err := parseCarListParams(c, true)
+	if err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !applyOrganization(c, &params, false) {
+		return
+	}
+
+	hits, total, err := cc.Repos.Cars.Search(c.Request.Context(), user.ID, keyword, params)
+	if errors.Is(err, repositories.ErrNoSearchTerms) {
+		apierrors.Respond(c, http.StatusBadRequest, "Keyword must contain letters or digits")
+		return
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to search cars")
+		return
+	}
+
+	results := make([]CarSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, newCarSearchResult(hit))
+	}
+
+	pagination := newPagination(params.Page, params.PageSize, total)
+	setLinkHeader(c, pagination)
+
+	writeJSONIfModified(c, CarSearchPage{Data: results, Pagination: pagination})
+}
+
+// uploadImages stores each uploaded file and returns their public URLs. If
+// any upload fails, the images stored so far are removed again.
+func (cc *CarController) uploadImages(c *gin.Context, files []*multipart.FileHeader) ([]string, error) {
+	urls := []string{}
+	for _, fileHeader := range files {
+		key, err := cc.putImage(c, fileHeader)
+		if err != nil {
+			cc.deleteImages(c, urls)
+			return nil, err
+		}
+		urls = append(urls, cc.Store.PublicURL(key))
+	}
+	return urls, nil
+}
+
+func (cc *CarController) putImage(c *gin.Context, fileHeader *multipart.FileHeader) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return cc.Store.Put(c.Request.Context(), fileHeader.Filename, file)
+}
+
+// deleteImages removes images from storage on a best-effort basis. URLs that
+// do not belong to the configured store are skipped.
+func (cc *CarController) deleteImages(c *gin.Context, urls []string) {
+	if err := storage.DeleteURLs(c.Request.Context(), cc.Store, urls); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to delete images", "error", err)
+	}
+}
+
+// applyVehicleForm copies the vehicle detail fields present in the form onto
+// the car. As with title and description, empty values leave a field as is.
+func applyVehicleForm(c *gin.Context, car *models.Car) error {
+	stringFields := map[string]*string{
+		"make":               &car.Make,
+		"model":              &car.ModelName,
+		"trim":               &car.Trim,
+		"body_type":          &car.BodyType,
+		"fuel_type":          &car.FuelType,
+		"transmission":       &car.Transmission,
+		"colour":             &car.Colour,
+		"registration_plate": &car.RegistrationPlate,
+		"vin":                &car.VIN,
+	}
+	for name, field := range stringFields {
+		if value := c.PostForm(name); value != "" {
+			*field = value
+		}
+	}
+
+	intFields := map[string]**int{
+		"year":    &car.Year,
+		"mileage": &car.Mileage,
+	}
+	for name, field := range intFields {
+		value := strings.TrimSpace(c.PostForm(name))
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return &models.FieldError{Field: name, Message: fmt.Sprintf("%s must be a whole number", name)}
+		}
+		*field = &n
+	}
+
+	return nil
+}
+
+// vehicleConflict checks that no other car of the same owner already uses
+// the car's VIN or registration plate. It returns a user-facing message
+// when there is a conflict.
+func (cc *CarController) vehicleConflict(c *gin.Context, car models.Car) (string, error) {
+	column, err := cc.Repos.Cars.VehicleConflict(c.Request.Context(), car)
+	switch {
+	case err != nil:
+		return "", err
+	case column == "vin":
+		return "A car with this VIN already exists", nil
+	case column == "registration_plate":
+		return "A car with this registration plate already exists", nil
+	}
+	return "", nil
 }
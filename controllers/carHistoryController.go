@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// CarRevisionPage is a page of a car's change history
+type CarRevisionPage struct {
+	Data       []models.CarRevision `json:"data"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// GetCarHistory lists the changes made to a car
+// @Summary Get car history
+// @Description Get a page of a car's revisions, newest first. Each revision records who made the change, the action and the old and new value of every changed field. Cars in the trash keep their history.
+// @Tags Cars
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} CarRevisionPage
+// @Success 304 "Not modified"
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/history [get]
+func (cc *CarController) GetCarHistory(c *gin.Context) {
+	car, ok := cc.findCar(c, viewCar, cc.Repos.Cars.FindByIDUnscoped)
+	if !ok {
+		return
+	}
+
+	page, pageSize := parsePage(c)
+	revisions, total, err := cc.Repos.Revisions.ListByCar(c.Request.Context(), car.ID, page, pageSize)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch history")
+		return
+	}
+
+	pagination := newPagination(page, pageSize, total)
+	setLinkHeader(c, pagination)
+
+	writeJSONIfModified(c, CarRevisionPage{Data: revisions, Pagination: pagination})
+}
+
+// RevertCar restores a car's fields to an earlier revision
+// @Summary Revert a car
+// @Description Set the car's fields back to their values right after the given revision. Images are left as they are, since removed images are no longer stored. The revert is recorded as a new revision.
+// @Tags Cars
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param revision path int true "Revision ID"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/history/{revision}/revert [post]
+func (cc *CarController) RevertCar(c *gin.Context) {
+	car, ok := cc.loadCar(c, editCar)
+	if !ok {
+		return
+	}
+	before := car
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	revisionID, ok := parseID(c, "revision")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Revision not found")
+		return
+	}
+
+	revision, err := cc.Repos.Revisions.FindByID(c.Request.Context(), revisionID)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && revision.CarID != car.ID) {
+		apierrors.Respond(c, http.StatusNotFound, "Revision not found")
+		return
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch history")
+		return
+	}
+
+	revisions, err := cc.Repos.Revisions.ListByCarUpTo(c.Request.Context(), car.ID, revision.ID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch history")
+		return
+	}
+
+	fields := models.ReplayCarRevisions(revisions)
+	delete(fields, "images")
+	if err := car.SetFields(fields); err != nil {
+		apierrors.Internal(c, err, "Failed to revert car")
+		return
+	}
+
+	car.Normalize()
+	if err := car.Validate(); err != nil {
+		apierrors.Invalid(c, err)
+		return
+	}
+
+	if msg, err := cc.vehicleConflict(c, car); err != nil {
+		apierrors.Internal(c, err, "Failed to revert car")
+		return
+	} else if msg != "" {
+		apierrors.RespondCode(c, http.StatusConflict, apierrors.CodeDuplicateVehicle, msg)
+		return
+	}
+
+	err = recordCarChange(c, cc.Repos, models.RevisionRevert, &before, &car, func(cars repositories.CarRepository) error {
+		return cars.Save(c.Request.Context(), &car)
+	})
+	if err != nil {
+		writeCarWriteError(c, err, "Failed to revert car")
+		return
+	}
+
+	writeCar(c, http.StatusOK, car)
+}
+
+// recordCarChange runs write in a transaction together with recording the
+// revision that describes it. before is nil for new cars; write must use
+// the transaction's repository it is given.
+func recordCarChange(c *gin.Context, repos repositories.Repositories, action string, before, car *models.Car, write func(cars repositories.CarRepository) error) error {
+	user := c.MustGet("user").(models.User)
+	return repos.Transaction(c.Request.Context(), func(tx repositories.Repositories) error {
+		if err := write(tx.Cars); err != nil {
+			return err
+		}
+		revision := models.NewCarRevision(user.ID, action, before, car)
+		return tx.Revisions.Record(c.Request.Context(), &revision)
+	})
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteCarImage deletes a single image of a car
+// @Summary Delete a car image
+// @Description Remove the image at the given position from the car and from storage
+// @Tags Car Images
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param index path int true "Image position (0-based)"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/images/{index} [delete]
+func (cc *CarController) DeleteCarImage(c *gin.Context) {
+	car, ok := cc.loadCar(c, editCar)
+	if !ok {
+		return
+	}
+	before := car
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	index, ok := imageIndex(c, car)
+	if !ok {
+		return
+	}
+
+	removed := car.Images[index]
+	images := append([]string{}, car.Images[:index]...)
+	car.Images = append(images, car.Images[index+1:]...)
+
+	if err := cc.saveImages(c, before, &car); err != nil {
+		writeCarWriteError(c, err, "Failed to update car")
+		return
+	}
+
+	cc.deleteImages(c, []string{removed})
+
+	writeCar(c, http.StatusOK, car)
+}
+
+// ReorderCarImages changes the order of a car's images
+// @Summary Reorder car images
+// @Description Replace the image order. The list must contain exactly the car's current image URLs. The first image is the cover image.
+// @Tags Car Images
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param body body object true "Image URLs in the new order"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/images/order [put]
+func (cc *CarController) ReorderCarImages(c *gin.Context) {
+	car, ok := cc.loadCar(c, editCar)
+	if !ok {
+		return
+	}
+	before := car
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	var input struct {
+		Images []string `json:"images" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	if !isPermutation(car.Images, input.Images) {
+		apierrors.Respond(c, http.StatusBadRequest, "Images must contain exactly the car's current images")
+		return
+	}
+
+	car.Images = input.Images
+	if err := cc.saveImages(c, before, &car); err != nil {
+		writeCarWriteError(c, err, "Failed to update car")
+		return
+	}
+
+	writeCar(c, http.StatusOK, car)
+}
+
+// SetPrimaryCarImage makes an image the cover image of a car
+// @Summary Set the cover image
+// @Description Move the image at the given position to the front of the list, making it the car's cover image
+// @Tags Car Images
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param index path int true "Image position (0-based)"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/images/{index}/primary [put]
+func (cc *CarController) SetPrimaryCarImage(c *gin.Context) {
+	car, ok := cc.loadCar(c, editCar)
+	if !ok {
+		return
+	}
+	before := car
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	index, ok := imageIndex(c, car)
+	if !ok {
+		return
+	}
+
+	images := []string{car.Images[index]}
+	for i, url := range car.Images {
+		if i != index {
+			images = append(images, url)
+		}
+	}
+	car.Images = images
+
+	if err := cc.saveImages(c, before, &car); err != nil {
+		writeCarWriteError(c, err, "Failed to update car")
+		return
+	}
+
+	writeCar(c, http.StatusOK, car)
+}
+
+// saveImages stores the car's new image list and records the change
+func (cc *CarController) saveImages(c *gin.Context, before models.Car, car *models.Car) error {
+	return recordCarChange(c, cc.Repos, models.RevisionUpdate, &before, car, func(cars repositories.CarRepository) error {
+		return cars.UpdateImages(c.Request.Context(), car)
+	})
+}
+
+// imageIndex parses the :index parameter and checks it against the car's images
+func imageIndex(c *gin.Context, car models.Car) (int, bool) {
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 || index >= len(car.Images) {
+		apierrors.Respond(c, http.StatusNotFound, "Image not found")
+		return 0, false
+	}
+	return index, true
+}
+
+// isPermutation reports whether b contains exactly the elements of a
+func isPermutation(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		if counts[s] == 0 {
+			return false
+		}
+		counts[s]--
+	}
+	return true
+}
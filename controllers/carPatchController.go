@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPatchSize limits the size of PATCH request bodies
+const maxPatchSize = 1 << 20
+
+// PatchCar partially updates a car
+// @Summary Patch a car
+// @Description Change individual fields of a car with a JSON Merge Patch (RFC 7396, application/merge-patch+json or application/json) or a JSON Patch (RFC 6902, application/json-patch+json).
+// @Description The patched document has the fields title, description, tags, make, model, year, trim, body_type, fuel_type, transmission, mileage, colour, registration_plate and vin. Images are managed through the image endpoints.
+// @Description In a merge patch null clears a field. With JSON Patch, add "/tags/-" appends a tag and remove "/tags/N" removes one; a failing test operation returns 409.
+// @Description The result is validated like a new car.
+// @Tags Cars
+// @Accept application/merge-patch+json,application/json-patch+json,json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param patch body object true "Merge patch object or JSON Patch operations"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 415 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id} [patch]
+func (cc *CarController) PatchCar(c *gin.Context) {
+	car, ok := cc.loadCar(c, editCar)
+	if !ok {
+		return
+	}
+	before := car
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != "application/merge-patch+json" && mediaType != "application/json" && mediaType != "application/json-patch+json" {
+		apierrors.Respond(c, http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json or application/json-patch+json")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	if err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+
+	fields := car.Fields()
+	delete(fields, "images")
+	document, err := json.Marshal(fields)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to update car")
+		return
+	}
+
+	var patched []byte
+	if mediaType == "application/json-patch+json" {
+		var patch jsonpatch.Patch
+		patch, err = jsonpatch.DecodePatch(body)
+		if err == nil {
+			patched, err = patch.Apply(document)
+		}
+	} else {
+		patched, err = jsonpatch.MergePatch(document, body)
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		apierrors.Respond(c, http.StatusConflict, "Patch test failed")
+		return
+	} else if err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, "Invalid patch: "+err.Error())
+		return
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, "Patch must produce an object")
+		return
+	}
+
+	// Removed fields are cleared; anything new is not a patchable field
+	for name, empty := range (&models.Car{}).Fields() {
+		if _, ok := result[name]; !ok && name != "images" {
+			result[name] = empty
+		}
+	}
+	for name := range result {
+		if _, ok := fields[name]; !ok {
+			apierrors.Invalid(c, &models.FieldError{Field: name, Message: "Unknown field: " + name})
+			return
+		}
+	}
+
+	if err := car.SetFields(result); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			apierrors.Invalid(c, &models.FieldError{Field: typeErr.Field, Message: "Invalid value for " + typeErr.Field})
+			return
+		}
+		apierrors.Respond(c, http.StatusBadRequest, "Invalid patch")
+		return
+	}
+
+	car.Normalize()
+	if err := car.Validate(); err != nil {
+		apierrors.Invalid(c, err)
+		return
+	}
+
+	if msg, err := cc.vehicleConflict(c, car); err != nil {
+		apierrors.Internal(c, err, "Failed to update car")
+		return
+	} else if msg != "" {
+		apierrors.RespondCode(c, http.StatusConflict, apierrors.CodeDuplicateVehicle, msg)
+		return
+	}
+
+	err = recordCarChange(c, cc.Repos, models.RevisionUpdate, &before, &car, func(cars repositories.CarRepository) error {
+		return cars.Save(c.Request.Context(), &car)
+	})
+	if err != nil {
+		writeCarWriteError(c, err, "Failed to update car")
+		return
+	}
+
+	writeCar(c, http.StatusOK, car)
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// PublishCar publishes a car
+// @Summary Publish a car
+// @Description Give a car a public, read-only page at /api/public/cars/{slug}, where anyone can see its listing details but not its VIN, registration plate or owner. The slug is random and cannot be guessed. Publishing a car that is already published keeps its slug. Only the owner can publish a car.
+// @Tags Cars
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/publish [post]
+func (cc *CarController) PublishCar(c *gin.Context) {
+	car, ok := cc.loadCar(c, ownCar)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	if car.IsPublished() {
+		writeCar(c, http.StatusOK, car)
+		return
+	}
+
+	cc.setPublicSlug(c, &car, "Failed to publish car")
+}
+
+// RotatePublicSlug gives a published car a new slug
+// @Summary Rotate a car's public slug
+// @Description Move a published car's public page to a new slug, so that links to the old one stop working
+// @Tags Cars
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/publish/rotate [post]
+func (cc *CarController) RotatePublicSlug(c *gin.Context) {
+	car, ok := cc.loadCar(c, ownCar)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	if !car.IsPublished() {
+		apierrors.Respond(c, http.StatusBadRequest, "Car is not published")
+		return
+	}
+
+	cc.setPublicSlug(c, &car, "Failed to rotate slug")
+}
+
+// UnpublishCar takes down a car's public page
+// @Summary Unpublish a car
+// @Description Remove a car's public page. Publishing it again gives it a new slug.
+// @Tags Cars
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/publish [delete]
+func (cc *CarController) UnpublishCar(c *gin.Context) {
+	car, ok := cc.loadCar(c, ownCar)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	if !car.IsPublished() {
+		writeCar(c, http.StatusOK, car)
+		return
+	}
+
+	if err := cc.Repos.Cars.SetPublicSlug(c.Request.Context(), &car, nil); err != nil {
+		writeCarWriteError(c, err, "Failed to unpublish car")
+		return
+	}
+
+	writeCar(c, http.StatusOK, car)
+}
+
+// setPublicSlug publishes the car under a new random slug and writes the
+// response
+func (cc *CarController) setPublicSlug(c *gin.Context, car *models.Car, message string) {
+	slug, err := utils.GeneratePublicSlug()
+	if err != nil {
+		apierrors.Internal(c, err, message)
+		return
+	}
+
+	if err := cc.Repos.Cars.SetPublicSlug(c.Request.Context(), car, &slug); err != nil {
+		writeCarWriteError(c, err, message)
+		return
+	}
+
+	writeCar(c, http.StatusOK, *car)
+}
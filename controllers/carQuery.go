@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// CarPage is a page of cars returned by the list and search endpoints
+type CarPage struct {
+	Data       []models.Car `json:"data"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+// carSorts are the accepted sort parameter values besides relevance
+var carSorts = map[string]bool{
+	repositories.SortCreatedAt: true,
+	repositories.SortUpdatedAt: true,
+	repositories.SortTitle:     true,
+}
+
+// parseCarListParams reads the list options from the query string. Search
+// results additionally accept sort=relevance, which is their default.
+func parseCarListParams(c *gin.Context, search bool) (repositories.CarListOptions, error) {
+	params := repositories.CarListOptions{Sort: repositories.SortCreatedAt, Desc: true}
+	if search {
+		params.Sort = repositories.SortRelevance
+	}
+	params.Page, params.PageSize = parsePage(c)
+
+	if sort := c.Query("sort"); sort != "" {
+		if !carSorts[sort] && !(search && sort == repositories.SortRelevance) {
+			if search {
+				return params, fmt.Errorf("sort must be one of: relevance, created_at, updated_at, title")
+			}
+			return params, fmt.Errorf("sort must be one of: created_at, updated_at, title")
+		}
+		params.Sort = sort
+		// Titles read naturally A-Z, timestamps and relevance highest first
+		params.Desc = sort != repositories.SortTitle
+	}
+
+	switch strings.ToLower(c.Query("order")) {
+	case "":
+	case "asc":
+		params.Desc = false
+	case "desc":
+		params.Desc = true
+	default:
+		return params, fmt.Errorf("order must be asc or desc")
+	}
+
+	switch scope := c.Query("scope"); scope {
+	case "":
+		params.Scope = repositories.ScopeOwned
+	case repositories.ScopeOwned, repositories.ScopeShared, repositories.ScopeAll:
+		params.Scope = scope
+	default:
+		return params, fmt.Errorf("scope must be one of: owned, shared, all")
+	}
+
+	params.TagsAny = splitTags(c.Query("tags_any"))
+	params.TagsAll = splitTags(c.Query("tags_all"))
+
+	var err error
+	if params.CreatedAfter, err = parseTimeParam(c, "created_after"); err != nil {
+		return params, err
+	}
+	if params.CreatedBefore, err = parseTimeParam(c, "created_before"); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+// splitTags parses a comma-separated tag list, dropping empty entries
+func splitTags(value string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseTimeParam accepts either an RFC 3339 timestamp or a YYYY-MM-DD date
+func parseTimeParam(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+)
+
+// CarSearchResult is a car matching a search, with its relevance and
+// HTML-escaped snippets where matches are wrapped in <mark> tags
+type CarSearchResult struct {
+	models.Car
+	Rank       float64       `json:"rank"`
+	Highlights CarHighlights `json:"highlights"`
+}
+
+type CarHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// CarSearchPage is a page of search results
+type CarSearchPage struct {
+	Data       []CarSearchResult `json:"data"`
+	Pagination Pagination        `json:"pagination"`
+}
+
+func newCarSearchResult(hit repositories.CarSearchHit) CarSearchResult {
+	return CarSearchResult{
+		Car:  hit.Car,
+		Rank: hit.Rank,
+		Highlights: CarHighlights{
+			Title:       hit.TitleHighlight,
+			Description: hit.DescriptionHighlight,
+		},
+	}
+}
@@ -0,0 +1,450 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// CarShareResult is a share of a car with the user who accepted it, if
+// anyone has yet
+type CarShareResult struct {
+	models.CarShare
+	Username string `json:"username"`
+}
+
+// SharedCar is a car shared with the user, with the role they were given
+type SharedCar struct {
+	models.Car
+	ShareRole string `json:"share_role"`
+}
+
+// SharedCarPage is a page of cars shared with the user
+type SharedCarPage struct {
+	Data       []SharedCar `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// ListCarShares lists who a car is shared with
+// @Summary List car shares
+// @Description List the email addresses a car is shared with, including invitations that have not been accepted yet. Only the owner can see them.
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Success 200 {array} CarShareResult
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/shares [get]
+func (cc *CarController) ListCarShares(c *gin.Context) {
+	car, ok := cc.loadCar(c, ownCar)
+	if !ok {
+		return
+	}
+
+	shares, err := cc.Repos.Shares.ListByCar(c.Request.Context(), car.ID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch shares")
+		return
+	}
+
+	results := make([]CarShareResult, 0, len(shares))
+	for _, share := range shares {
+		result := CarShareResult{CarShare: share}
+		if share.User != nil {
+			result.Username = share.User.Username
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// ShareCar invites another user to a car
+// @Summary Share a car
+// @Description Invite an email address to view ("viewer") or also edit ("editor") a car. The share takes effect once the user with that verified address accepts it, signing up first if they have no account; the response is the same either way. Only the owner can share a car.
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param body body object true "Email and role"
+// @Success 201 {object} models.CarShare
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/shares [post]
+func (cc *CarController) ShareCar(c *gin.Context) {
+	car, ok := cc.loadCar(c, ownCar)
+	if !ok {
+		return
+	}
+	owner := c.MustGet("user").(models.User)
+	if car.OrganizationID != nil {
+		apierrors.Respond(c, http.StatusBadRequest, "Fleet cars are shared through organization membership")
+		return
+	}
+
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+	if !models.ValidShareRole(input.Role) {
+		apierrors.Respond(c, http.StatusBadRequest, "Role must be viewer or editor")
+		return
+	}
+
+	// The invitation goes to the address whether or not it has an account,
+	// so that sharing cannot be used to find out which addresses do
+	email := models.NormalizeEmail(input.Email)
+	if email == models.NormalizeEmail(owner.Email) {
+		apierrors.Respond(c, http.StatusBadRequest, "You cannot share a car with yourself")
+		return
+	}
+
+	share := models.CarShare{
+		CarID:     car.ID,
+		Email:     email,
+		InvitedBy: owner.ID,
+		Role:      input.Role,
+	}
+	err := cc.Repos.Shares.Create(c.Request.Context(), &share)
+	if errors.Is(err, repositories.ErrShareExists) {
+		apierrors.Respond(c, http.StatusConflict, "Car is already shared with this email")
+		return
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to share car")
+		return
+	}
+
+	if err := cc.sendShareInvitation(c.Request.Context(), owner, car, share); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to send share invitation", "error", err)
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+// UpdateCarShare changes the role of a share
+// @Summary Change a share's role
+// @Description Switch a share between viewer and editor. Only the owner can change it.
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param share path int true "Share ID"
+// @Param body body object true "New role"
+// @Success 200 {object} models.CarShare
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/shares/{share} [put]
+func (cc *CarController) UpdateCarShare(c *gin.Context) {
+	car, ok := cc.loadCar(c, ownCar)
+	if !ok {
+		return
+	}
+
+	share, ok := cc.loadShare(c, car.ID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+	if !models.ValidShareRole(input.Role) {
+		apierrors.Respond(c, http.StatusBadRequest, "Role must be viewer or editor")
+		return
+	}
+
+	if err := cc.Repos.Shares.UpdateRole(c.Request.Context(), &share, input.Role); err != nil {
+		apierrors.Internal(c, err, "Failed to update share")
+		return
+	}
+
+	c.JSON(http.StatusOK, share)
+}
+
+// RevokeCarShare removes a share
+// @Summary Revoke a share
+// @Description Remove a share or invitation. The owner can revoke any share of the car; the shared user can remove their own to leave the car.
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param share path int true "Share ID"
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/shares/{share} [delete]
+func (cc *CarController) RevokeCarShare(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	carID, ok := parseID(c, "id")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Share not found")
+		return
+	}
+
+	share, ok := cc.loadShare(c, carID)
+	if !ok {
+		return
+	}
+
+	// Anyone but the shared user needs to own the car
+	if !share.IsFor(user.ID) {
+		if _, ok := cc.loadCar(c, ownCar); !ok {
+			return
+		}
+	}
+
+	if err := cc.Repos.Shares.Delete(c.Request.Context(), &share); err != nil {
+		apierrors.Internal(c, err, "Failed to revoke share")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share revoked"})
+}
+
+// ListCarInvitations lists the shares waiting for the user to accept them
+// @Summary List share invitations
+// @Description List the cars other users have invited the logged-in user to, newest first
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.CarShare
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/invitations [get]
+func (cc *CarController) ListCarInvitations(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	shares, err := cc.Repos.Shares.ListPendingForEmail(c.Request.Context(), models.NormalizeEmail(user.Email))
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+// AcceptCarInvitation accepts a share
+// @Summary Accept a share invitation
+// @Description Accept an invitation so that the car shows up in the user's shared cars. The user's email address must be verified.
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Param share path int true "Share ID"
+// @Success 200 {object} models.CarShare
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/invitations/{share}/accept [post]
+func (cc *CarController) AcceptCarInvitation(c *gin.Context) {
+	share, ok := cc.loadInvitation(c)
+	if !ok {
+		return
+	}
+
+	// Anyone can sign up with any address, so only a verified email proves
+	// the invitation was meant for this user
+	user := c.MustGet("user").(models.User)
+	if !user.Verified {
+		apierrors.RespondCode(c, http.StatusForbidden, apierrors.CodeEmailNotVerified, "Verify your email address to accept invitations")
+		return
+	}
+
+	if !share.IsAccepted() {
+		if err := cc.Repos.Shares.Accept(c.Request.Context(), &share, user.ID); err != nil {
+			apierrors.Internal(c, err, "Failed to accept invitation")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, share)
+}
+
+// DeclineCarInvitation declines a share
+// @Summary Decline a share invitation
+// @Description Decline an invitation, removing it
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Param share path int true "Share ID"
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/invitations/{share} [delete]
+func (cc *CarController) DeclineCarInvitation(c *gin.Context) {
+	share, ok := cc.loadInvitation(c)
+	if !ok {
+		return
+	}
+
+	if err := cc.Repos.Shares.Delete(c.Request.Context(), &share); err != nil {
+		apierrors.Internal(c, err, "Failed to decline invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined"})
+}
+
+// ListSharedCars lists the cars shared with the user
+// @Summary List cars shared with me
+// @Description Get a page of the cars other users have shared with the logged-in user, with the role they were given. Accepts the same sorting and filtering parameters as the car list.
+// @Tags Car Sharing
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, updated_at, title) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param tags_any query string false "Only cars with at least one of these tags (comma-separated)"
+// @Param tags_all query string false "Only cars with all of these tags (comma-separated)"
+// @Param created_after query string false "Only cars created at or after this RFC 3339 time or date"
+// @Param created_before query string false "Only cars created before this RFC 3339 time or date"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} SharedCarPage
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/shared [get]
+func (cc *CarController) ListSharedCars(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	params, err := parseCarListParams(c, false)
+	if err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	params.Scope = repositories.ScopeShared
+
+	cars, total, err := cc.Repos.Cars.ListForUser(c.Request.Context(), user.ID, params)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch cars")
+		return
+	}
+
+	ids := make([]uint, 0, len(cars))
+	for _, car := range cars {
+		ids = append(ids, car.ID)
+	}
+	shares, err := cc.Repos.Shares.FindAcceptedForCars(c.Request.Context(), user.ID, ids)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch cars")
+		return
+	}
+
+	data := make([]SharedCar, 0, len(cars))
+	for _, car := range cars {
+		data = append(data, SharedCar{Car: car, ShareRole: shares[car.ID].Role})
+	}
+
+	pagination := newPagination(params.Page, params.PageSize, total)
+	setLinkHeader(c, pagination)
+
+	writeJSONIfModified(c, SharedCarPage{Data: data, Pagination: pagination})
+}
+
+// loadShare loads the share named by the :share parameter and checks that
+// it belongs to the given car, writing the error response if not
+func (cc *CarController) loadShare(c *gin.Context, carID uint) (models.CarShare, bool) {
+	id, ok := parseID(c, "share")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Share not found")
+		return models.CarShare{}, false
+	}
+
+	share, err := cc.Repos.Shares.FindByID(c.Request.Context(), id)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && share.CarID != carID) {
+		apierrors.Respond(c, http.StatusNotFound, "Share not found")
+		return models.CarShare{}, false
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch share")
+		return models.CarShare{}, false
+	}
+
+	return share, true
+}
+
+// loadInvitation loads the share named by the :share parameter and checks
+// that it was sent to the authenticated user's email and not yet accepted
+// by someone else, writing the error response if not
+func (cc *CarController) loadInvitation(c *gin.Context) (models.CarShare, bool) {
+	user := c.MustGet("user").(models.User)
+
+	id, ok := parseID(c, "share")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Invitation not found")
+		return models.CarShare{}, false
+	}
+
+	// Other users' invitations are reported as missing so that their IDs
+	// cannot be probed
+	share, err := cc.Repos.Shares.FindByID(c.Request.Context(), id)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && !invitedUser(share, user)) {
+		apierrors.Respond(c, http.StatusNotFound, "Invitation not found")
+		return models.CarShare{}, false
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch invitation")
+		return models.CarShare{}, false
+	}
+
+	return share, true
+}
+
+// invitedUser reports whether the share was sent to the user and is still
+// theirs to accept or decline
+func invitedUser(share models.CarShare, user models.User) bool {
+	if share.UserID != nil {
+		return *share.UserID == user.ID
+	}
+	return share.Email == models.NormalizeEmail(user.Email)
+}
+
+// sendShareInvitation tells the invited address about a new share
+func (cc *CarController) sendShareInvitation(ctx context.Context, owner models.User, car models.Car, share models.CarShare) error {
+	return cc.Mailer.Send(ctx, mailer.Message{
+		To:      share.Email,
+		Subject: "A car was shared with you on Car Management",
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s invited you to %s their car \"%s\". Sign in with this email address, or sign up if you have no account yet, and open the link below to accept the invitation.\n\n%s/shared\n",
+			owner.Username, shareVerb(share.Role), car.Title, cc.Cfg.AppURL,
+		),
+	})
+}
+
+// shareVerb describes what a share role allows, for invitation emails
+func shareVerb(role string) string {
+	if role == models.ShareEditor {
+		return "view and edit"
+	}
+	return "view"
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrash lists the deleted cars of the logged-in user
+// @Summary List deleted cars
+// @Description Get a page of the logged-in user's cars in the trash, most recently deleted first, or the active organization's for its owners and managers. Cars are purged permanently once the retention period has passed.
+// @Tags Cars
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param X-Organization-ID header int false "Act in this organization instead of the token's; 0 for personal cars"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} CarPage
+// @Success 304 "Not modified"
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/trash [get]
+func (cc *CarController) ListTrash(c *gin.Context) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	user := userInterface.(models.User)
+
+	var params repositories.CarListOptions
+	params.Page, params.PageSize = parsePage(c)
+	if !applyOrganization(c, &params, true) {
+		return
+	}
+
+	cars, total, err := cc.Repos.Cars.ListDeletedByOwner(c.Request.Context(), user.ID, params)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch cars")
+		return
+	}
+
+	pagination := newPagination(params.Page, params.PageSize, total)
+	setLinkHeader(c, pagination)
+
+	writeJSONIfModified(c, CarPage{Data: cars, Pagination: pagination})
+}
+
+// RestoreCar restores a deleted car
+// @Summary Restore a deleted car
+// @Description Move a car out of the trash
+// @Tags Cars
+// @Accept json
+// @Produce json
+// @Param id path int true "Car ID"
+// @Param If-Match header string false "Only apply the change if the car still has this ETag"
+// @Param Idempotency-Key header string false "Replay the original response when the request is retried with the same key"
+// @Success 200 {object} models.Car
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 412 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/cars/{id}/restore [post]
+func (cc *CarController) RestoreCar(c *gin.Context) {
+	car, ok := cc.findCar(c, ownCar, cc.Repos.Cars.FindByIDUnscoped)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(c, car) {
+		return
+	}
+
+	if !car.DeletedAt.Valid {
+		apierrors.Respond(c, http.StatusBadRequest, "Car is not in the trash")
+		return
+	}
+
+	// Another car may have taken the VIN or plate in the meantime
+	if msg, err := cc.vehicleConflict(c, car); err != nil {
+		apierrors.Internal(c, err, "Failed to restore car")
+		return
+	} else if msg != "" {
+		apierrors.RespondCode(c, http.StatusConflict, apierrors.CodeDuplicateVehicle, msg)
+		return
+	}
+
+	before := car
+	err := recordCarChange(c, cc.Repos, models.RevisionRestore, &before, &car, func(cars repositories.CarRepository) error {
+		return cars.Restore(c.Request.Context(), &car)
+	})
+	if err != nil {
+		writeCarWriteError(c, err, "Failed to restore car")
+		return
+	}
+
+	writeCar(c, http.StatusOK, car)
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// carETag is the strong entity tag of a car, derived from its version
+func carETag(car models.Car) string {
+	return `"` + strconv.Itoa(car.Version) + `"`
+}
+
+// writeCar responds with the car and its ETag
+func writeCar(c *gin.Context, status int, car models.Car) {
+	c.Header("ETag", carETag(car))
+	c.JSON(status, car)
+}
+
+// writeCarIfModified responds with the car, or with 304 Not Modified if
+// the client's If-None-Match already names its current version
+func writeCarIfModified(c *gin.Context, car models.Car) {
+	etag := carETag(car)
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag, true) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, car)
+}
+
+// writeJSONIfModified responds with body and a weak ETag computed from its
+// encoding, or with 304 Not Modified if If-None-Match already names it
+func writeJSONIfModified(c *gin.Context, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to encode response")
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag, true) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// checkIfMatch enforces an If-Match precondition against the car's current
+// version, responding 412 Precondition Failed if it does not hold. Requests
+// without If-Match are allowed.
+func checkIfMatch(c *gin.Context, car models.Car) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" || etagMatches(header, carETag(car), false) {
+		return true
+	}
+	c.Header("ETag", carETag(car))
+	apierrors.RespondCode(c, http.StatusPreconditionFailed, apierrors.CodeVersionConflict, "Car has been modified; reload it and try again")
+	return false
+}
+
+// writeCarWriteError responds to a failed car write. Losing a race against
+// another writer is reported like a failed If-Match, or as 409 Conflict if
+// the client sent no precondition.
+func writeCarWriteError(c *gin.Context, err error, message string) {
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		status := http.StatusConflict
+		if c.GetHeader("If-Match") != "" {
+			status = http.StatusPreconditionFailed
+		}
+		apierrors.RespondCode(c, status, apierrors.CodeVersionConflict, "Car has been modified; reload it and try again")
+		return
+	}
+	apierrors.Internal(c, err, message)
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header value
+// names etag. If-Match uses the strong comparison, under which weak tags
+// never match; If-None-Match uses the weak one.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if candidate == etag && !strings.HasPrefix(candidate, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestContext returns a gin context for a GET request with the given
+// headers, and the recorder of its response
+func newTestContext(headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		c.Request.Header.Set(name, value)
+	}
+	return c, w
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		etag   string
+		weak   bool
+		want   bool
+	}{
+		{"same strong tag", `"3"`, `"3"`, false, true},
+		{"other version", `"2"`, `"3"`, false, false},
+		{"one of a list", `"1", "3"`, `"3"`, false, true},
+		{"list without spaces", `"1","3"`, `"3"`, false, true},
+		{"wildcard", `*`, `"3"`, false, true},
+		{"weak tag under strong comparison", `W/"3"`, `"3"`, false, false},
+		{"weak tag under weak comparison", `W/"3"`, `"3"`, true, true},
+		{"strong tag against weak etag", `"abc"`, `W/"abc"`, true, true},
+		{"weak wildcard", `*`, `W/"abc"`, true, true},
+		{"unquoted", `3`, `"3"`, true, false},
+		{"empty", ``, `"3"`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.header, tt.etag, tt.weak); got != tt.want {
+				t.Errorf("etagMatches(%q, %q, %v) = %v, want %v", tt.header, tt.etag, tt.weak, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	car := models.Car{Version: 3}
+	tests := []struct {
+		name    string
+		ifMatch string
+		ok      bool
+	}{
+		{"no precondition", "", true},
+		{"current version", `"3"`, true},
+		{"wildcard", `*`, true},
+		{"stale version", `"2"`, false},
+		{"weak tag", `W/"3"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if tt.ifMatch != "" {
+				headers["If-Match"] = tt.ifMatch
+			}
+			c, w := newTestContext(headers)
+			if ok := checkIfMatch(c, car); ok != tt.ok {
+				t.Fatalf("checkIfMatch = %v, want %v", ok, tt.ok)
+			}
+			if tt.ok {
+				return
+			}
+			if w.Code != http.StatusPreconditionFailed {
+				t.Errorf("status = %d, want 412", w.Code)
+			}
+			if etag := w.Header().Get("ETag"); etag != `"3"` {
+				t.Errorf("ETag = %q, want the current version", etag)
+			}
+		})
+	}
+}
+
+func TestWriteCarIfModified(t *testing.T) {
+	car := models.Car{Version: 3}
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{"no precondition", "", http.StatusOK},
+		{"current version", `"3"`, http.StatusNotModified},
+		{"weak current version", `W/"3"`, http.StatusNotModified},
+		{"stale version", `"2"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newTestContext(map[string]string{"If-None-Match": tt.ifNoneMatch})
+			writeCarIfModified(c, car)
+			c.Writer.WriteHeaderNow()
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if etag := w.Header().Get("ETag"); etag != `"3"` {
+				t.Errorf("ETag = %q, want %q", etag, `"3"`)
+			}
+			if tt.status == http.StatusNotModified && w.Body.Len() > 0 {
+				t.Errorf("304 response has a body: %s", w.Body)
+			}
+		})
+	}
+}
+
+func TestWriteJSONIfModified(t *testing.T) {
+	body := gin.H{"cars": []string{"a", "b"}}
+
+	c, w := newTestContext(nil)
+	writeJSONIfModified(c, body)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || len(etag) < 4 || etag[:2] != "W/" {
+		t.Fatalf("status = %d, ETag = %q, want 200 with a weak ETag", w.Code, etag)
+	}
+
+	c, w = newTestContext(map[string]string{"If-None-Match": etag})
+	writeJSONIfModified(c, body)
+	c.Writer.WriteHeaderNow()
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d with a matching If-None-Match, want 304", w.Code)
+	}
+
+	c, w = newTestContext(map[string]string{"If-None-Match": etag})
+	writeJSONIfModified(c, gin.H{"cars": []string{"a"}})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("status = %d, ETag = %q for a changed body, want 200 with a new ETag", w.Code, w.Header().Get("ETag"))
+	}
+}
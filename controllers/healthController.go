@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// readinessTimeout bounds each readiness check, so that a hanging
+// dependency fails the probe instead of stalling it
+const readinessTimeout = 2 * time.Second
+
+// HealthController answers liveness and readiness probes
+type HealthController struct {
+	DB    *gorm.DB
+	Store storage.ImageStore
+}
+
+// HealthStatus is the result of a health probe. Checks lists each
+// dependency as "ok" or "unavailable".
+type HealthStatus struct {
+	Status string            `json:"status" example:"ok"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Healthz godoc
+// @Summary Liveness probe
+// @Description Report that the server is running. Dependencies are not checked.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} HealthStatus
+// @Router /healthz [get]
+func (hc *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthStatus{Status: "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Check that the database and the image storage backend are reachable
+// @Tags Health
+// @Produce json
+// @Success 200 {object} HealthStatus
+// @Failure 503 {object} HealthStatus
+// @Router /readyz [get]
+func (hc *HealthController) Readyz(c *gin.Context) {
+	checks := map[string]func(context.Context) error{
+		"database": hc.pingDB,
+		"storage":  hc.Store.Ping,
+	}
+
+	result := HealthStatus{Status: "ok", Checks: map[string]string{}}
+	status := http.StatusOK
+	for name, check := range checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		err := check(ctx)
+		cancel()
+
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Readiness check failed", "check", name, "error", err)
+			result.Checks[name] = "unavailable"
+			result.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		result.Checks[name] = "ok"
+	}
+
+	c.JSON(status, result)
+}
+
+func (hc *HealthController) pingDB(ctx context.Context) error {
+	sqlDB, err := hc.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
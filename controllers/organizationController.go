@@ -0,0 +1,759 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OrganizationController manages organizations, their members and
+// invitations. Switching organizations updates the session on DB.
+type OrganizationController struct {
+	DB     *gorm.DB
+	Repos  repositories.Repositories
+	Cfg    config.Config
+	Mailer mailer.Mailer
+}
+
+// OrganizationResult is an organization with the caller's role in it
+type OrganizationResult struct {
+	models.Organization
+	Role string `json:"role"`
+}
+
+// OrganizationMemberResult is a member of an organization with their user
+type OrganizationMemberResult struct {
+	models.OrganizationMember
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// CreateOrganization godoc
+// @Summary Create an organization
+// @Description Create an organization with the logged-in user as its owner
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param body body object true "Organization name"
+// @Success 201 {object} OrganizationResult
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations [post]
+func (oc *OrganizationController) CreateOrganization(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var input struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		apierrors.Respond(c, http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	org := models.Organization{Name: name, CreatedBy: user.ID}
+	member, err := oc.Repos.Organizations.Create(c.Request.Context(), &org)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to create organization")
+		return
+	}
+
+	c.JSON(http.StatusCreated, OrganizationResult{Organization: org, Role: member.Role})
+}
+
+// ListOrganizations godoc
+// @Summary List my organizations
+// @Description List the organizations the logged-in user belongs to, with their role in each
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Success 200 {array} OrganizationResult
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations [get]
+func (oc *OrganizationController) ListOrganizations(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	memberships, err := oc.Repos.Organizations.ListMemberships(c.Request.Context(), user.ID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch organizations")
+		return
+	}
+
+	results := make([]OrganizationResult, 0, len(memberships))
+	for _, member := range memberships {
+		if member.Organization != nil {
+			results = append(results, OrganizationResult{Organization: *member.Organization, Role: member.Role})
+		}
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// GetOrganization godoc
+// @Summary Get an organization
+// @Description Get an organization the logged-in user belongs to
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {object} OrganizationResult
+// @Failure 401 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id} [get]
+func (oc *OrganizationController) GetOrganization(c *gin.Context) {
+	org, member, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationResult{Organization: org, Role: member.Role})
+}
+
+// UpdateOrganization godoc
+// @Summary Rename an organization
+// @Description Change an organization's name. Only owners can do this.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param body body object true "New name"
+// @Success 200 {object} OrganizationResult
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id} [put]
+func (oc *OrganizationController) UpdateOrganization(c *gin.Context) {
+	org, member, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+	if member.Role != models.OrgOwner {
+		apierrors.Respond(c, http.StatusForbidden, "Only organization owners can do this")
+		return
+	}
+
+	var input struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		apierrors.Respond(c, http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	if err := oc.Repos.Organizations.Rename(c.Request.Context(), &org, name); err != nil {
+		apierrors.Internal(c, err, "Failed to update organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, OrganizationResult{Organization: org, Role: member.Role})
+}
+
+// DeleteOrganization godoc
+// @Summary Delete an organization
+// @Description Delete an organization with its memberships and invitations. Only owners can do this, and only once the organization has no cars left, including in the trash.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id} [delete]
+func (oc *OrganizationController) DeleteOrganization(c *gin.Context) {
+	org, member, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+	if member.Role != models.OrgOwner {
+		apierrors.Respond(c, http.StatusForbidden, "Only organization owners can do this")
+		return
+	}
+
+	count, err := oc.Repos.Organizations.CountCars(c.Request.Context(), org.ID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to delete organization")
+		return
+	}
+	if count > 0 {
+		apierrors.Respond(c, http.StatusConflict, "Delete the organization's cars first")
+		return
+	}
+
+	if err := oc.Repos.Organizations.Delete(c.Request.Context(), &org); err != nil {
+		apierrors.Internal(c, err, "Failed to delete organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organization deleted"})
+}
+
+// SwitchOrganization godoc
+// @Summary Switch the active organization
+// @Description Make an organization the session's active one, or go back to personal cars with organization_id 0. Returns a new access token carrying the organization; refreshed tokens keep it. A single request can override it with the X-Organization-ID header.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param body body object true "Organization ID, 0 for personal cars"
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/active [put]
+func (oc *OrganizationController) SwitchOrganization(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	session := c.MustGet("session").(models.Session)
+
+	var input struct {
+		OrganizationID *uint `json:"organization_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	var orgID *uint
+	if *input.OrganizationID != 0 {
+		if _, err := oc.Repos.Organizations.FindMember(c.Request.Context(), *input.OrganizationID, user.ID); errors.Is(err, repositories.ErrNotFound) {
+			apierrors.Respond(c, http.StatusForbidden, "You are not a member of this organization")
+			return
+		} else if err != nil {
+			apierrors.Internal(c, err, "Failed to switch organization")
+			return
+		}
+		orgID = input.OrganizationID
+	}
+
+	if err := oc.DB.Model(&session).Update("organization_id", orgID).Error; err != nil {
+		apierrors.Internal(c, err, "Failed to switch organization")
+		return
+	}
+	session.OrganizationID = orgID
+
+	token, err := utils.GenerateToken(user.ID, session.ID, session.ActiveOrganizationID(), oc.Cfg.JWTSecret, oc.Cfg.AccessTokenTTL)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to generate token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":           token,
+		"organization_id": session.ActiveOrganizationID(),
+		"expires_in":      int(oc.Cfg.AccessTokenTTL.Seconds()),
+	})
+}
+
+// ListMembers godoc
+// @Summary List organization members
+// @Description List the members of an organization the logged-in user belongs to
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {array} OrganizationMemberResult
+// @Failure 401 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id}/members [get]
+func (oc *OrganizationController) ListMembers(c *gin.Context) {
+	org, _, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	members, err := oc.Repos.Organizations.ListMembers(c.Request.Context(), org.ID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch members")
+		return
+	}
+
+	results := make([]OrganizationMemberResult, 0, len(members))
+	for _, member := range members {
+		results = append(results, newOrganizationMemberResult(member))
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// UpdateMember godoc
+// @Summary Change a member's role
+// @Description Change the role of a member. Only owners can do this, and an organization always keeps at least one owner.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param user path int true "User ID"
+// @Param body body object true "New role"
+// @Success 200 {object} models.OrganizationMember
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id}/members/{user} [put]
+func (oc *OrganizationController) UpdateMember(c *gin.Context) {
+	org, caller, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+	if caller.Role != models.OrgOwner {
+		apierrors.Respond(c, http.StatusForbidden, "Only organization owners can do this")
+		return
+	}
+
+	member, ok := oc.loadMember(c, org.ID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+	if !models.ValidOrgRole(input.Role) {
+		apierrors.Respond(c, http.StatusBadRequest, "Role must be owner, manager or driver")
+		return
+	}
+
+	if member.Role == models.OrgOwner && input.Role != models.OrgOwner && !oc.hasOtherOwner(c, org.ID) {
+		return
+	}
+
+	if err := oc.Repos.Organizations.UpdateMemberRole(c.Request.Context(), &member, input.Role); err != nil {
+		apierrors.Internal(c, err, "Failed to update member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember godoc
+// @Summary Remove a member
+// @Description Remove a member from an organization. Owners can remove anyone, managers can remove drivers, and every member can leave. An organization always keeps at least one owner.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param user path int true "User ID"
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id}/members/{user} [delete]
+func (oc *OrganizationController) RemoveMember(c *gin.Context) {
+	org, caller, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+
+	member, ok := oc.loadMember(c, org.ID)
+	if !ok {
+		return
+	}
+
+	allowed := member.UserID == caller.UserID ||
+		caller.Role == models.OrgOwner ||
+		(caller.Role == models.OrgManager && member.Role == models.OrgDriver)
+	if !allowed {
+		apierrors.Respond(c, http.StatusForbidden, "You cannot remove this member")
+		return
+	}
+
+	if member.Role == models.OrgOwner && !oc.hasOtherOwner(c, org.ID) {
+		return
+	}
+
+	if err := oc.Repos.Organizations.RemoveMember(c.Request.Context(), &member); err != nil {
+		apierrors.Internal(c, err, "Failed to remove member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// InviteMember godoc
+// @Summary Invite a member
+// @Description Invite an email address to join an organization. Owners can invite any role; managers can invite managers and drivers. The invitation is accepted by the user with that verified email.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param body body object true "Email and role"
+// @Success 201 {object} models.OrganizationInvitation
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id}/invitations [post]
+func (oc *OrganizationController) InviteMember(c *gin.Context) {
+	org, caller, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(models.User)
+
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+	if !models.ValidOrgRole(input.Role) {
+		apierrors.Respond(c, http.StatusBadRequest, "Role must be owner, manager or driver")
+		return
+	}
+	if !caller.CanInvite(input.Role) {
+		apierrors.Respond(c, http.StatusForbidden, "You cannot invite members with this role")
+		return
+	}
+
+	email := models.NormalizeEmail(input.Email)
+	if invitee, err := oc.Repos.Users.FindByEmail(c.Request.Context(), email); err == nil {
+		if _, err := oc.Repos.Organizations.FindMember(c.Request.Context(), org.ID, invitee.ID); err == nil {
+			apierrors.Respond(c, http.StatusConflict, "User is already a member of the organization")
+			return
+		}
+	}
+
+	invitation := models.OrganizationInvitation{
+		OrganizationID: org.ID,
+		Email:          email,
+		Role:           input.Role,
+		InvitedBy:      user.ID,
+		ExpiresAt:      time.Now().Add(oc.Cfg.OrgInvitationTTL),
+	}
+	if err := oc.Repos.Organizations.CreateInvitation(c.Request.Context(), &invitation); err != nil {
+		apierrors.Internal(c, err, "Failed to create invitation")
+		return
+	}
+
+	if err := oc.sendInvitation(c.Request.Context(), user, org, invitation); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to send organization invitation", "error", err)
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations godoc
+// @Summary List organization invitations
+// @Description List the invitations sent for an organization that have not been accepted. Only owners and managers can see them.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {array} models.OrganizationInvitation
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id}/invitations [get]
+func (oc *OrganizationController) ListInvitations(c *gin.Context) {
+	org, caller, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+	if !caller.ManagesCars() {
+		apierrors.Respond(c, http.StatusForbidden, "Only organization owners and managers can do this")
+		return
+	}
+
+	invitations, err := oc.Repos.Organizations.ListInvitations(c.Request.Context(), org.ID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke an organization invitation
+// @Description Withdraw an invitation before it is accepted. Only owners and managers can do this.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param invitation path int true "Invitation ID"
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/{id}/invitations/{invitation} [delete]
+func (oc *OrganizationController) RevokeInvitation(c *gin.Context) {
+	org, caller, ok := oc.loadOrganization(c)
+	if !ok {
+		return
+	}
+	if !caller.ManagesCars() {
+		apierrors.Respond(c, http.StatusForbidden, "Only organization owners and managers can do this")
+		return
+	}
+
+	id, ok := parseID(c, "invitation")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Invitation not found")
+		return
+	}
+	invitation, err := oc.Repos.Organizations.FindInvitation(c.Request.Context(), id)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && invitation.OrganizationID != org.ID) {
+		apierrors.Respond(c, http.StatusNotFound, "Invitation not found")
+		return
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch invitation")
+		return
+	}
+
+	if err := oc.Repos.Organizations.DeleteInvitation(c.Request.Context(), &invitation); err != nil {
+		apierrors.Internal(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// ListMyInvitations godoc
+// @Summary List my organization invitations
+// @Description List the unexpired invitations sent to the logged-in user's email, newest first
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.OrganizationInvitation
+// @Failure 401 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/invitations [get]
+func (oc *OrganizationController) ListMyInvitations(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	invitations, err := oc.Repos.Organizations.ListInvitationsForEmail(c.Request.Context(), models.NormalizeEmail(user.Email))
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept an organization invitation
+// @Description Join the organization with the invited role. The user's email must match the invitation and be verified.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param invitation path int true "Invitation ID"
+// @Success 200 {object} models.OrganizationMember
+// @Failure 401 {object} apierrors.Response
+// @Failure 403 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 410 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/invitations/{invitation}/accept [post]
+func (oc *OrganizationController) AcceptInvitation(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	invitation, ok := oc.loadMyInvitation(c)
+	if !ok {
+		return
+	}
+	if invitation.IsExpired() {
+		apierrors.Respond(c, http.StatusGone, "Invitation has expired")
+		return
+	}
+
+	// Anyone can sign up with any address, so only a verified email proves
+	// the invitation was meant for this user
+	if !user.Verified {
+		apierrors.RespondCode(c, http.StatusForbidden, apierrors.CodeEmailNotVerified, "Verify your email address to accept invitations")
+		return
+	}
+
+	member, err := oc.Repos.Organizations.AcceptInvitation(c.Request.Context(), &invitation, user.ID)
+	if errors.Is(err, repositories.ErrMemberExists) {
+		apierrors.Respond(c, http.StatusConflict, "You are already a member of this organization")
+		return
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to accept invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// DeclineInvitation godoc
+// @Summary Decline an organization invitation
+// @Description Decline an invitation sent to the logged-in user's email, removing it
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param invitation path int true "Invitation ID"
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/organizations/invitations/{invitation} [delete]
+func (oc *OrganizationController) DeclineInvitation(c *gin.Context) {
+	invitation, ok := oc.loadMyInvitation(c)
+	if !ok {
+		return
+	}
+
+	if err := oc.Repos.Organizations.DeleteInvitation(c.Request.Context(), &invitation); err != nil {
+		apierrors.Internal(c, err, "Failed to decline invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined"})
+}
+
+// loadOrganization loads the organization named by the :id parameter along
+// with the caller's membership, writing the error response if the
+// organization does not exist or the caller is not a member. Non-members get
+// 404 so that organization IDs cannot be probed.
+func (oc *OrganizationController) loadOrganization(c *gin.Context) (models.Organization, models.OrganizationMember, bool) {
+	user := c.MustGet("user").(models.User)
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Organization not found")
+		return models.Organization{}, models.OrganizationMember{}, false
+	}
+
+	member, err := oc.Repos.Organizations.FindMember(c.Request.Context(), id, user.ID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		apierrors.Respond(c, http.StatusNotFound, "Organization not found")
+		return models.Organization{}, models.OrganizationMember{}, false
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch organization")
+		return models.Organization{}, models.OrganizationMember{}, false
+	}
+
+	org, err := oc.Repos.Organizations.FindByID(c.Request.Context(), id)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch organization")
+		return models.Organization{}, models.OrganizationMember{}, false
+	}
+
+	return org, member, true
+}
+
+// loadMember loads the membership of the user named by the :user parameter,
+// writing the error response if there is none
+func (oc *OrganizationController) loadMember(c *gin.Context, orgID uint) (models.OrganizationMember, bool) {
+	userID, ok := parseID(c, "user")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Member not found")
+		return models.OrganizationMember{}, false
+	}
+
+	member, err := oc.Repos.Organizations.FindMember(c.Request.Context(), orgID, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		apierrors.Respond(c, http.StatusNotFound, "Member not found")
+		return models.OrganizationMember{}, false
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch member")
+		return models.OrganizationMember{}, false
+	}
+
+	return member, true
+}
+
+// loadMyInvitation loads the invitation named by the :invitation parameter
+// and checks that it was sent to the caller's email, writing the error
+// response if not
+func (oc *OrganizationController) loadMyInvitation(c *gin.Context) (models.OrganizationInvitation, bool) {
+	user := c.MustGet("user").(models.User)
+
+	id, ok := parseID(c, "invitation")
+	if !ok {
+		apierrors.Respond(c, http.StatusNotFound, "Invitation not found")
+		return models.OrganizationInvitation{}, false
+	}
+
+	invitation, err := oc.Repos.Organizations.FindInvitation(c.Request.Context(), id)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && invitation.Email != models.NormalizeEmail(user.Email)) {
+		apierrors.Respond(c, http.StatusNotFound, "Invitation not found")
+		return models.OrganizationInvitation{}, false
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch invitation")
+		return models.OrganizationInvitation{}, false
+	}
+
+	return invitation, true
+}
+
+// hasOtherOwner checks that the organization keeps an owner when one owner
+// steps down, writing the error response if not
+func (oc *OrganizationController) hasOtherOwner(c *gin.Context, orgID uint) bool {
+	owners, err := oc.Repos.Organizations.CountOwners(c.Request.Context(), orgID)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to update member")
+		return false
+	}
+	if owners < 2 {
+		apierrors.Respond(c, http.StatusConflict, "An organization needs at least one owner")
+		return false
+	}
+	return true
+}
+
+// sendInvitation emails an organization invitation
+func (oc *OrganizationController) sendInvitation(ctx context.Context, inviter models.User, org models.Organization, invitation models.OrganizationInvitation) error {
+	return oc.Mailer.Send(ctx, mailer.Message{
+		To:      invitation.Email,
+		Subject: "You are invited to an organization on Car Management",
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s invited you to join %s as a %s. Sign in with this email address and open the link below to accept. The invitation expires in %s.\n\n%s/invitations\n",
+			inviter.Username, org.Name, invitation.Role, oc.Cfg.OrgInvitationTTL, oc.Cfg.AppURL,
+		),
+	})
+}
+
+func newOrganizationMemberResult(member models.OrganizationMember) OrganizationMemberResult {
+	result := OrganizationMemberResult{OrganizationMember: member}
+	if member.User != nil {
+		result.Username = member.User.Username
+		result.Email = member.User.Email
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Pagination describes the page returned by a list endpoint
+type Pagination struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	NextPage   *int  `json:"next_page"`
+	PrevPage   *int  `json:"prev_page"`
+}
+
+func newPagination(page, pageSize int, total int64) Pagination {
+	p := Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+	if page < p.TotalPages {
+		next := page + 1
+		p.NextPage = &next
+	}
+	if page > 1 {
+		prev := page - 1
+		p.PrevPage = &prev
+	}
+	return p
+}
+
+// parsePage reads page and page_size query parameters with sane bounds
+func parsePage(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// setLinkHeader adds an RFC 8288 Link header with first, prev, next and last
+// relations pointing at the same request with a different page
+func setLinkHeader(c *gin.Context, p Pagination) {
+	pageURL := func(page int) string {
+		u := url.URL{Path: c.Request.URL.Path}
+		q := c.Request.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("page_size", strconv.Itoa(p.PageSize))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if p.PrevPage != nil {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(*p.PrevPage)))
+	}
+	if p.NextPage != nil {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(*p.NextPage)))
+	}
+	if p.TotalPages > 0 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(p.TotalPages)))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+}
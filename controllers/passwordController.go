@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a single-use password reset link to the user. Always succeeds so that registered emails cannot be discovered.
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "Account email"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Router /api/users/password/forgot [post]
+func (ac *AuthController) ForgotPassword(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	response := gin.H{"message": "If an account exists for this email, a reset link has been sent"}
+
+	user, err := ac.Users.FindByEmail(c.Request.Context(), input.Email)
+	if err != nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// Only the most recent link should work
+	if err := models.InvalidateUserTokens(ac.DB, user.ID, models.TokenPurposePasswordReset); err != nil {
+		apierrors.Internal(c, err, "Failed to create reset token")
+		return
+	}
+
+	token, err := ac.issueUserToken(ac.DB, user.ID, models.TokenPurposePasswordReset, ac.Cfg.PasswordResetTTL)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to create reset token")
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Car Management password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to reset your password. It expires in %s and can only be used once.\n\n%s/reset-password?token=%s\n\nIf you did not request this, you can ignore this email.\n",
+			user.Username, ac.Cfg.PasswordResetTTL, ac.Cfg.AppURL, token,
+		),
+	}
+	if err := ac.Mailer.Send(c.Request.Context(), msg); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to send password reset email", "error", err)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ResetPassword godoc
+// @Summary Reset a password
+// @Description Set a new password using a reset token. All existing sessions of the user are revoked.
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "Reset token and new password"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/password/reset [post]
+func (ac *AuthController) ResetPassword(c *gin.Context) {
+	var input struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=6"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	var resetToken models.UserToken
+	if err := ac.DB.Where("token_hash = ? AND purpose = ?", utils.HashToken(input.Token), models.TokenPurposePasswordReset).
+		First(&resetToken).Error; err != nil || !resetToken.IsUsable() {
+		apierrors.RespondCode(c, http.StatusBadRequest, apierrors.CodeInvalidToken, "Invalid or expired reset token")
+		return
+	}
+
+	user, err := ac.Users.FindByID(c.Request.Context(), resetToken.UserID)
+	if err != nil {
+		apierrors.RespondCode(c, http.StatusBadRequest, apierrors.CodeInvalidToken, "Invalid or expired reset token")
+		return
+	}
+
+	user.Password = input.Password
+	if err := user.HashPassword(); err != nil {
+		apierrors.Internal(c, err, "Failed to hash password")
+		return
+	}
+
+	err = ac.DB.Transaction(func(tx *gorm.DB) error {
+		// Claim the token first so a concurrent request cannot reuse it
+		result := tx.Model(&models.UserToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTokenAlreadyUsed
+		}
+
+		if err := tx.Model(&user).Update("password", user.Password).Error; err != nil {
+			return err
+		}
+		if err := models.InvalidateUserTokens(tx, user.ID, models.TokenPurposePasswordReset); err != nil {
+			return err
+		}
+		return models.RevokeUserSessions(tx, user.ID)
+	})
+	if err == errTokenAlreadyUsed {
+		apierrors.RespondCode(c, http.StatusBadRequest, apierrors.CodeInvalidToken, "Invalid or expired reset token")
+		return
+	}
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to reset password")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
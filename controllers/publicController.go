@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// PublicController serves published cars without authentication
+type PublicController struct {
+	Repos repositories.Repositories
+}
+
+// PublicCarPage is a page of published cars
+type PublicCarPage struct {
+	Data       []models.PublicCar `json:"data"`
+	Pagination Pagination         `json:"pagination"`
+}
+
+// PublicCarSearchResult is a published car matching a search
+type PublicCarSearchResult struct {
+	models.PublicCar
+	Rank       float64       `json:"rank"`
+	Highlights CarHighlights `json:"highlights"`
+}
+
+// PublicCarSearchPage is a page of search results over published cars
+type PublicCarSearchPage struct {
+	Data       []PublicCarSearchResult `json:"data"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// GetPublicCar godoc
+// @Summary Get a published car
+// @Description Get the public details of a published car by its slug. No authentication is needed.
+// @Tags Public
+// @Accept json
+// @Produce json
+// @Param slug path string true "Public slug"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} models.PublicCar
+// @Success 304 "Not modified"
+// @Failure 404 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/public/cars/{slug} [get]
+func (pc *PublicController) GetPublicCar(c *gin.Context) {
+	car, err := pc.Repos.Cars.FindByPublicSlug(c.Request.Context(), c.Param("slug"))
+	if errors.Is(err, repositories.ErrNotFound) {
+		apierrors.Respond(c, http.StatusNotFound, "Car not found")
+		return
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch car")
+		return
+	}
+
+	writeJSONIfModified(c, car.Public())
+}
+
+// ListPublicCars godoc
+// @Summary List published cars
+// @Description Get a page of published cars, newest first by default. No authentication is needed.
+// @Tags Public
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, updated_at, title) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param tags_any query string false "Only cars with at least one of these tags (comma-separated)"
+// @Param tags_all query string false "Only cars with all of these tags (comma-separated)"
+// @Param created_after query string false "Only cars created at or after this RFC 3339 time or date"
+// @Param created_before query string false "Only cars created before this RFC 3339 time or date"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} PublicCarPage
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/public/cars [get]
+func (pc *PublicController) ListPublicCars(c *gin.Context) {
+	params, err := parseCarListParams(c, false)
+	if err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	params.Scope = repositories.ScopePublished
+
+	cars, total, err := pc.Repos.Cars.ListForUser(c.Request.Context(), 0, params)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to fetch cars")
+		return
+	}
+
+	results := make([]models.PublicCar, 0, len(cars))
+	for _, car := range cars {
+		results = append(results, car.Public())
+	}
+
+	pagination := newPagination(params.Page, params.PageSize, total)
+	setLinkHeader(c, pagination)
+
+	writeJSONIfModified(c, PublicCarPage{Data: results, Pagination: pagination})
+}
+
+// SearchPublicCars godoc
+// @Summary Search published cars
+// @Description Full-text search over the published cars, with the same query syntax and filters as the authenticated search. No authentication is needed.
+// @Tags Public
+// @Accept json
+// @Produce json
+// @Param keyword query string true "Search query"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(relevance, created_at, updated_at, title) default(relevance)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param tags_any query string false "Only cars with at least one of these tags (comma-separated)"
+// @Param tags_all query string false "Only cars with all of these tags (comma-separated)"
+// @Param created_after query string false "Only cars created at or after this RFC 3339 time or date"
+// @Param created_before query string false "Only cars created before this RFC 3339 time or date"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} PublicCarSearchPage
+// @Success 304 "Not modified"
+// @Failure 400 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/public/cars/search [get]
+func (pc *PublicController) SearchPublicCars(c *gin.Context) {
+	keyword := c.Query("keyword")
+	if keyword == "" {
+		apierrors.Respond(c, http.StatusBadRequest, "Keyword query parameter is required")
+		return
+	}
+
+	params, err := parseCarListParams(c, true)
+	if err != nil {
+		apierrors.Respond(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	params.Scope = repositories.ScopePublished
+
+	hits, total, err := pc.Repos.Cars.Search(c.Request.Context(), 0, keyword, params)
+	if errors.Is(err, repositories.ErrNoSearchTerms) {
+		apierrors.Respond(c, http.StatusBadRequest, "Keyword must contain letters or digits")
+		return
+	} else if err != nil {
+		apierrors.Internal(c, err, "Failed to search cars")
+		return
+	}
+
+	results := make([]PublicCarSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		result := newCarSearchResult(hit)
+		results = append(results, PublicCarSearchResult{
+			PublicCar:  result.Car.Public(),
+			Rank:       result.Rank,
+			Highlights: result.Highlights,
+		})
+	}
+
+	pagination := newPagination(params.Page, params.PageSize, total)
+	setLinkHeader(c, pagination)
+
+	writeJSONIfModified(c, PublicCarSearchPage{Data: results, Pagination: pagination})
+}
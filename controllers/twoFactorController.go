@@ -0,0 +1,335 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/metrics"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const recoveryCodeCount = 10
+
+// SetupTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a new TOTP secret and return it with an otpauth:// URI for authenticator apps. Two-factor is not enforced until confirmed.
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Success 200 {object} object
+// @Failure 401 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/2fa/setup [post]
+func (ac *AuthController) SetupTwoFactor(c *gin.Context) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	user := userInterface.(models.User)
+
+	if user.TOTPEnabled {
+		apierrors.Respond(c, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to generate secret")
+		return
+	}
+
+	if err := ac.Users.Update(c.Request.Context(), &user, map[string]interface{}{"totp_secret": secret, "totp_last_counter": 0}); err != nil {
+		apierrors.Internal(c, err, "Failed to start enrollment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      secret,
+		"otpauth_url": utils.TOTPURI(ac.Cfg.TOTPIssuer, user.Email, secret),
+	})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Confirm two-factor enrollment
+// @Description Enable two-factor authentication by submitting the first code from the authenticator app. Returns one-time recovery codes, which are shown only once.
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "TOTP code"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 409 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/2fa/confirm [post]
+func (ac *AuthController) ConfirmTwoFactor(c *gin.Context) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	user := userInterface.(models.User)
+
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	if user.TOTPEnabled {
+		apierrors.Respond(c, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+	if user.TOTPSecret == "" {
+		apierrors.Respond(c, http.StatusBadRequest, "Two-factor enrollment has not been started")
+		return
+	}
+
+	counter, ok := utils.ValidateTOTP(user.TOTPSecret, input.Code, time.Now(), user.TOTPLastCounter)
+	if !ok {
+		apierrors.RespondCode(c, http.StatusBadRequest, apierrors.CodeInvalidCode, "Invalid code")
+		return
+	}
+
+	var codes []string
+	err := ac.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"totp_enabled":      true,
+			"totp_last_counter": counter,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to enable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication. Requires the account password and a current TOTP or recovery code.
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "Password and TOTP or recovery code"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 429 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/2fa/disable [post]
+func (ac *AuthController) DisableTwoFactor(c *gin.Context) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	user := userInterface.(models.User)
+
+	var input struct {
+		Password     string `json:"password" binding:"required"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	if !user.TOTPEnabled {
+		apierrors.Respond(c, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		return
+	}
+
+	// Wrong passwords and codes count towards the same lockout as logins
+	account := loginAccount(user.ID, "")
+	if ac.loginLocked(c, account) {
+		return
+	}
+
+	if !user.CheckPassword(input.Password) {
+		ac.loginFailed(c, account)
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidCredentials, "Invalid password")
+		return
+	}
+
+	ok, err := ac.verifySecondFactor(user, input.Code, input.RecoveryCode)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to verify code")
+		return
+	}
+	if !ok {
+		ac.loginFailed(c, account)
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidCode, "Invalid code")
+		return
+	}
+	ac.loginSucceeded(c, account)
+
+	err = ac.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"totp_enabled":      false,
+			"totp_secret":       "",
+			"totp_last_counter": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error
+	})
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// LoginTwoFactor godoc
+// @Summary Complete a two-factor login
+// @Description Exchange the challenge token returned by login, together with a TOTP or recovery code, for access and refresh tokens
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "Challenge token and TOTP or recovery code"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 401 {object} apierrors.Response
+// @Failure 429 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/login/2fa [post]
+func (ac *AuthController) LoginTwoFactor(c *gin.Context) {
+	var input struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	claims, err := utils.ValidateToken(input.ChallengeToken, ac.Cfg.JWTSecret)
+	if err != nil || claims.Purpose != utils.TokenPurposeTwoFactor {
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Invalid or expired challenge token")
+		return
+	}
+
+	user, err := ac.Users.FindByID(c.Request.Context(), claims.UserID)
+	if err != nil || !user.TOTPEnabled || user.IsDisabled() {
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Invalid or expired challenge token")
+		return
+	}
+
+	account := loginAccount(user.ID, "")
+	if ac.loginLocked(c, account) {
+		metrics.Logins.WithLabelValues(metrics.LoginLocked).Inc()
+		return
+	}
+
+	ok, err := ac.verifySecondFactor(user, input.Code, input.RecoveryCode)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to verify code")
+		return
+	}
+	if !ok {
+		ac.loginFailed(c, account)
+		metrics.Logins.WithLabelValues(metrics.LoginFailure).Inc()
+		apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidCode, "Invalid code")
+		return
+	}
+	ac.loginSucceeded(c, account)
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+
+	token, refreshToken, err := ac.issueTokens(c, user)
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to generate token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":            user.ID,
+		"username":      user.Username,
+		"email":         user.Email,
+		"verified":      user.Verified,
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(ac.Cfg.AccessTokenTTL.Seconds()),
+	})
+}
+
+// verifySecondFactor accepts either a TOTP code or an unused recovery code
+// and consumes it so it cannot be replayed
+func (ac *AuthController) verifySecondFactor(user models.User, code, recoveryCode string) (bool, error) {
+	if code != "" {
+		counter, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastCounter)
+		if !ok {
+			return false, nil
+		}
+		// Conditional on the stored counter so the same code cannot be used
+		// by two concurrent requests
+		result := ac.DB.Model(&models.User{}).
+			Where("id = ? AND totp_last_counter < ?", user.ID, counter).
+			Update("totp_last_counter", counter)
+		return result.RowsAffected == 1, result.Error
+	}
+
+	if recoveryCode != "" {
+		result := ac.DB.Model(&models.RecoveryCode{}).
+			Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.HashToken(utils.NormalizeRecoveryCode(recoveryCode))).
+			Update("used_at", time.Now())
+		return result.RowsAffected == 1, result.Error
+	}
+
+	return false, nil
+}
+
+// replaceRecoveryCodes deletes the user's recovery codes and generates a new
+// set, returning the plaintext codes
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, models.RecoveryCode{UserID: userID, CodeHash: utils.HashToken(code)})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VerifyEmail godoc
+// @Summary Verify an email address
+// @Description Mark the user's email as verified using the token sent at signup
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "Verification token"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Failure 500 {object} apierrors.Response
+// @Router /api/users/verify [post]
+func (ac *AuthController) VerifyEmail(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	var verificationToken models.UserToken
+	if err := ac.DB.Where("token_hash = ? AND purpose = ?", utils.HashToken(input.Token), models.TokenPurposeEmailVerification).
+		First(&verificationToken).Error; err != nil || !verificationToken.IsUsable() {
+		apierrors.RespondCode(c, http.StatusBadRequest, apierrors.CodeInvalidToken, "Invalid or expired verification token")
+		return
+	}
+
+	now := time.Now()
+	err := ac.DB.Transaction(func(tx *gorm.DB) error {
+		if err := models.InvalidateUserTokens(tx, verificationToken.UserID, models.TokenPurposeEmailVerification); err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).
+			Where("id = ?", verificationToken.UserID).
+			Updates(map[string]interface{}{"verified": true, "verified_at": now}).Error
+	})
+	if err != nil {
+		apierrors.Internal(c, err, "Failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// ResendVerification godoc
+// @Summary Resend the verification email
+// @Description Send a new verification link to an unverified account. Always succeeds so that registered emails cannot be discovered.
+// @Tags Users
+// @Accept json
+// @Produce json
+//
+// @Param body body object true "Account email"
+//
+// @Success 200 {object} object
+// @Failure 400 {object} apierrors.Response
+// @Router /api/users/verify/resend [post]
+func (ac *AuthController) ResendVerification(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apierrors.Bind(c, err)
+		return
+	}
+
+	response := gin.H{"message": "If an unverified account exists for this email, a verification link has been sent"}
+
+	user, err := ac.Users.FindByEmail(c.Request.Context(), input.Email)
+	if err != nil || user.Verified {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := ac.sendVerificationEmail(c.Request.Context(), user); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to send verification email", "error", err)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sendVerificationEmail replaces any outstanding verification token for the
+// user with a new one and emails it
+func (ac *AuthController) sendVerificationEmail(ctx context.Context, user models.User) error {
+	if err := models.InvalidateUserTokens(ac.DB, user.ID, models.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	token, err := ac.issueUserToken(ac.DB, user.ID, models.TokenPurposeEmailVerification, ac.Cfg.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return ac.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Car Management email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s/verify-email?token=%s\n",
+			user.Username, ac.Cfg.EmailVerificationTTL, ac.Cfg.AppURL, token,
+		),
+	})
+}
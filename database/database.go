@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Models lists every model stored in the database
+var Models = []interface{}{
+	&models.User{},
+	&models.Car{},
+	&models.Session{},
+	&models.UserToken{},
+	&models.RecoveryCode{},
+	&models.CarRevision{},
+	&models.IdempotencyKey{},
+	&models.CarShare{},
+	&models.Organization{},
+	&models.OrganizationMember{},
+	&models.OrganizationInvitation{},
+}
+
+// Open connects to the database selected by DB_DRIVER. PostgreSQL schemas
+// are managed by the SQL migrations; SQLite databases are created with
+// AutoMigrate since the migrations use PostgreSQL-only features.
+func Open(cfg config.Config) (*gorm.DB, error) {
+	switch cfg.DBDriver {
+	case "", "postgres":
+		dsn := "host=" + cfg.DBHost + " user=" + cfg.DBUser + " password=" + cfg.DBPassword + " dbname=" + cfg.DBName + " port=" + cfg.DBPort + " sslmode=allow TimeZone=Asia/Kolkata"
+		return gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logging.NewGormLogger()})
+	case "sqlite":
+		return OpenSQLite(cfg.DBPath)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.DBDriver)
+	}
+}
+
+// OpenSQLite opens (or creates) an SQLite database and migrates its schema.
+// Use ":memory:" for a throwaway in-memory database.
+func OpenSQLite(path string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logging.NewGormLogger()})
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite serialises writers anyway, and a single connection keeps an
+	// in-memory database alive and shared across requests
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(Models...); err != nil {
+		return nil, fmt.Errorf("migrating sqlite schema: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyCleaner deletes idempotency keys whose replay window is over
+type IdempotencyKeyCleaner struct {
+	DB       *gorm.DB
+	Interval time.Duration
+}
+
+// Run cleans up on every interval until ctx is cancelled
+func (k *IdempotencyKeyCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(k.Interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := models.DeleteExpiredIdempotencyKeys(k.DB.WithContext(ctx)); err != nil {
+			logging.FromContext(ctx).Error("Idempotency key cleanup failed", "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("Deleted expired idempotency keys", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/storage"
+)
+
+// purgeBatchSize limits how many cars are loaded per query
+const purgeBatchSize = 100
+
+// CarPurger permanently deletes cars, and their stored images, once they
+// have been in the trash for longer than the retention period
+type CarPurger struct {
+	Cars      repositories.CarRepository
+	Store     storage.ImageStore
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// Run purges on every interval until ctx is cancelled. Several instances
+// may run at once; purging the same car twice is harmless.
+func (p *CarPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := p.Purge(ctx); err != nil {
+			logging.FromContext(ctx).Error("Trash purge failed", "purged", n, "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("Purged cars from the trash", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes every car whose retention period is over and returns how
+// many were deleted
+func (p *CarPurger) Purge(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-p.Retention)
+	purged := 0
+
+	for {
+		cars, err := p.Cars.FindDeletedBefore(ctx, cutoff, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for i := range cars {
+			// The row goes first: its version guard fails if the car was
+			// restored in the meantime, and a restored car must keep its
+			// images. Image errors are only logged, so a broken asset can
+			// leave an orphaned file but never a car without its images.
+			err := p.Cars.HardDelete(ctx, &cars[i])
+			if errors.Is(err, repositories.ErrVersionConflict) {
+				// Restored or already purged in the meantime
+				continue
+			} else if err != nil {
+				return purged, err
+			}
+			purged++
+
+			if err := storage.DeleteURLs(ctx, p.Store, cars[i].Images); err != nil {
+				logging.FromContext(ctx).Error("Failed to delete images", "car_id", cars[i].ID, "error", err)
+			}
+		}
+
+		if len(cars) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
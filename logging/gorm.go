@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is how long a query may take before it is logged
+const slowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger sends gorm's logs to the logger of each query's context, so
+// that database errors carry the ID of the request that caused them.
+// Failed queries are logged as errors, except when no record was found,
+// and slow queries as warnings.
+type GormLogger struct {
+	level gormlogger.LogLevel
+}
+
+// NewGormLogger returns a gorm logger that logs warnings and errors
+func NewGormLogger() *GormLogger {
+	return &GormLogger{level: gormlogger.Warn}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &GormLogger{level: level}
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		FromContext(ctx).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		FromContext(ctx).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		FromContext(ctx).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		FromContext(ctx).Error("Query failed", "error", err, "sql", sql, "rows", rows, "elapsed_ms", Milliseconds(elapsed))
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		FromContext(ctx).Warn("Slow query", "sql", sql, "rows", rows, "elapsed_ms", Milliseconds(elapsed))
+	case l.level >= gormlogger.Info:
+		sql, rows := fc()
+		FromContext(ctx).Debug("Query", "sql", sql, "rows", rows, "elapsed_ms", Milliseconds(elapsed))
+	}
+}
@@ -0,0 +1,63 @@
+// Package logging sets up the structured logger of the server and carries
+// per-request loggers through contexts, so that everything logged while
+// serving a request can be found by its request ID.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+)
+
+type contextKey struct{}
+
+// New returns the logger selected by LOG_FORMAT ("json" or "text") and
+// LOG_LEVEL ("debug", "info", "warn" or "error"), writing to stdout
+func New(cfg config.Config) *slog.Logger {
+	return newLogger(os.Stdout, cfg.LogFormat, cfg.LogLevel)
+}
+
+func newLogger(w io.Writer, format, level string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(level)}
+	if strings.EqualFold(format, "text") {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+func parseLevel(level string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With returns a copy of ctx whose logger adds the given attributes to
+// every record
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
+
+// Milliseconds converts a duration to fractional milliseconds for logging
+func Milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
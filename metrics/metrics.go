@@ -0,0 +1,97 @@
+// Package metrics collects Prometheus metrics about HTTP requests, logins,
+// image uploads and the database connection pool, served at /metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+// Login results
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+	LoginLocked  = "locked"
+)
+
+// Upload results
+const (
+	UploadSuccess = "success"
+	UploadFailure = "failure"
+)
+
+// Registry holds every metric of the server, plus the Go runtime and
+// process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to serve HTTP requests, by route template and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Login attempts, including two-factor codes, by result.",
+	}, []string{"result"})
+
+	Signups = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "auth_signups_total",
+		Help: "Accounts created.",
+	})
+
+	Uploads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_uploads_total",
+		Help: "Image uploads, by storage backend and result.",
+	}, []string{"backend", "result"})
+
+	UploadBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_upload_bytes_total",
+		Help: "Bytes of successfully uploaded images, by storage backend.",
+	}, []string{"backend"})
+
+	UploadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "storage_upload_duration_seconds",
+		Help:    "Time taken to upload an image, by storage backend.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"backend"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		Logins,
+		Signups,
+		Uploads,
+		UploadBytes,
+		UploadDuration,
+	)
+}
+
+// RegisterDB adds the connection pool statistics of db, such as open, idle
+// and waited-for connections
+func RegisterDB(db *gorm.DB, name string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return Registry.Register(collectors.NewDBStatsCollector(sqlDB, name))
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
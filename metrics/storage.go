@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/storage"
+)
+
+// instrumentedStore records the uploads made through an image store
+type instrumentedStore struct {
+	storage.ImageStore
+}
+
+// InstrumentStore wraps store so that its uploads are counted, sized and
+// timed under the store's name
+func InstrumentStore(store storage.ImageStore) storage.ImageStore {
+	return instrumentedStore{store}
+}
+
+func (s instrumentedStore) Put(ctx context.Context, filename string, r io.Reader) (string, error) {
+	backend := s.Name()
+	counter := &countingReader{r: r}
+
+	start := time.Now()
+	key, err := s.ImageStore.Put(ctx, filename, counter)
+	UploadDuration.WithLabelValues(backend).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		Uploads.WithLabelValues(backend, UploadFailure).Inc()
+		return key, err
+	}
+	Uploads.WithLabelValues(backend, UploadSuccess).Inc()
+	UploadBytes.WithLabelValues(backend).Add(float64(counter.n))
+	return key, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/akashkumar7902/car-management-backend/apierrors"
 	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/logging"
 	"github.com/akashkumar7902/car-management-backend/models"
 	"github.com/akashkumar7902/car-management-backend/utils"
 	"github.com/gin-gonic/gin"
@@ -15,35 +17,65 @@ func AuthMiddleware(db *gorm.DB, cfg config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
-			c.Abort()
+			apierrors.Respond(c, http.StatusUnauthorized, "Authorization header missing")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
-			c.Abort()
+			apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Invalid Authorization header format")
 			return
 		}
 
 		tokenStr := parts[1]
 		claims, err := utils.ValidateToken(tokenStr, cfg.JWTSecret)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+		if err != nil || claims.Purpose != "" {
+			apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Invalid or expired token")
+			return
+		}
+
+		var session models.Session
+		if err := db.First(&session, claims.SessionID).Error; err != nil || session.UserID != claims.UserID || !session.IsActive() {
+			apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Session expired or revoked")
 			return
 		}
 
 		var user models.User
 		if err := db.First(&user, claims.UserID).Error; err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-			c.Abort()
+			apierrors.Respond(c, http.StatusUnauthorized, "User not found")
 			return
 		}
 
-		// Attach user to context
+		if user.IsDisabled() {
+			apierrors.RespondCode(c, http.StatusForbidden, apierrors.CodeAccountDisabled, "Account disabled")
+			return
+		}
+
+		// Attach user and session to context, and tag the request's log
+		// records with the user
 		c.Set("user", user)
+		c.Set("session", session)
+		c.Set("claims", *claims)
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "user_id", user.ID))
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail rejects users who have not verified their email when
+// REQUIRE_EMAIL_VERIFICATION is enabled. Must run after AuthMiddleware.
+func RequireVerifiedEmail(cfg config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.RequireEmailVerification {
+			c.Next()
+			return
+		}
+
+		user := c.MustGet("user").(models.User)
+		if !user.Verified {
+			apierrors.RespondCode(c, http.StatusForbidden, apierrors.CodeEmailNotVerified, "Email address not verified")
+			return
+		}
+
 		c.Next()
 	}
 }
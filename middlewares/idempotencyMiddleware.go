@@ -0,0 +1,247 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxIdempotencyKeyLength bounds the Idempotency-Key header
+	maxIdempotencyKeyLength = 255
+
+	// maxIdempotentBodySize bounds the request bodies buffered to
+	// fingerprint them. It matches the 32 MB the car handlers parse
+	// multipart forms with, enough for a full set of images.
+	maxIdempotentBodySize = 32 << 20
+)
+
+// replayedHeaders are the response headers stored with a key and sent
+// again on replay
+var replayedHeaders = []string{"Content-Type", "ETag", "Location", "Link"}
+
+// Idempotency replays the stored response when a mutating request is retried
+// with the same Idempotency-Key header, so that a retry after a timeout does
+// not create a second car or upload the images again. Keys are scoped to the
+// user and kept for IDEMPOTENCY_KEY_TTL. Reusing a key for a different
+// request is rejected with 422, and a retry that arrives while the first
+// request is still running gets 409. Server errors are not stored, so the
+// request can be retried with the same key. Requests without the header are
+// not affected. Must run after AuthMiddleware.
+func Idempotency(db *gorm.DB, cfg config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			key = ""
+		}
+		userInterface, exists := c.Get("user")
+		if key == "" || !exists {
+			c.Next()
+			return
+		}
+		user := userInterface.(models.User)
+
+		if len(key) > maxIdempotencyKeyLength {
+			apierrors.Respond(c, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			apierrors.Respond(c, http.StatusRequestEntityTooLarge, "Request body too large")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		var organizationID uint
+		if member, ok := c.Get("membership"); ok {
+			organizationID = member.(models.OrganizationMember).OrganizationID
+		}
+		fingerprint := requestFingerprint(c.Request, organizationID, body)
+
+		record, created, err := claimIdempotencyKey(db, models.IdempotencyKey{
+			UserID:      user.ID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			Header:      map[string]string{},
+			ExpiresAt:   time.Now().Add(cfg.IdempotencyKeyTTL),
+		})
+		if err != nil {
+			apierrors.Internal(c, err, "Failed to process Idempotency-Key")
+			return
+		}
+
+		if !created {
+			replayIdempotentResponse(c, record, fingerprint)
+			return
+		}
+
+		// Release the key if the handler panics or fails, so that the
+		// request can be retried
+		completed := false
+		defer func() {
+			if !completed {
+				db.Delete(&record)
+			}
+		}()
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		header := map[string]string{}
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				header[name] = value
+			}
+		}
+		err = db.Model(&record).Updates(models.IdempotencyKey{
+			StatusCode: status,
+			Header:     header,
+			Body:       writer.body.Bytes(),
+		}).Error
+		completed = err == nil
+	}
+}
+
+// claimIdempotencyKey stores the key for a new request. If the key is
+// already taken, the existing record is returned instead; an expired one is
+// replaced.
+func claimIdempotencyKey(db *gorm.DB, record models.IdempotencyKey) (models.IdempotencyKey, bool, error) {
+	for {
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			return record, false, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return record, true, nil
+		}
+
+		var existing models.IdempotencyKey
+		err := db.Where(&models.IdempotencyKey{UserID: record.UserID, Key: record.Key}).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released in the meantime
+			continue
+		} else if err != nil {
+			return record, false, err
+		}
+
+		if time.Now().Before(existing.ExpiresAt) {
+			return existing, false, nil
+		}
+		if err := db.Where("expires_at < ?", time.Now()).Delete(&existing).Error; err != nil {
+			return record, false, err
+		}
+	}
+}
+
+// replayIdempotentResponse answers a retried request from the stored record
+func replayIdempotentResponse(c *gin.Context, record models.IdempotencyKey, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		apierrors.RespondCode(c, http.StatusUnprocessableEntity, apierrors.CodeIdempotencyKeyReused, "Idempotency-Key has already been used for a different request")
+	case !record.IsComplete():
+		apierrors.RespondCode(c, http.StatusConflict, apierrors.CodeRequestInProgress, "A request with this Idempotency-Key is still being processed")
+	default:
+		for name, value := range record.Header {
+			c.Header(name, value)
+		}
+		c.Header("Idempotent-Replayed", "true")
+		c.Status(record.StatusCode)
+		c.Writer.Write(record.Body)
+	}
+	c.Abort()
+}
+
+// requestFingerprint hashes the method, path, organization and body of a
+// request. The organization is the one the request acts in, 0 for none, so
+// that a key reused in another organization is not replayed there.
+// Multipart bodies are hashed part by part, since clients pick a new
+// boundary when they rebuild the body for a retry.
+func requestFingerprint(r *http.Request, organizationID uint, body []byte) string {
+	h := sha256.New()
+	writeField(h, []byte(r.Method))
+	writeField(h, []byte(r.URL.RequestURI()))
+	writeField(h, []byte(strconv.FormatUint(uint64(organizationID), 10)))
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "multipart/") || !hashMultipart(h, body, params["boundary"]) {
+		writeField(h, []byte(mediaType))
+		writeField(h, body)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashMultipart writes the name, filename and content of every part to h,
+// reporting false if the body is not valid multipart
+func hashMultipart(h hash.Hash, body []byte, boundary string) bool {
+	if boundary == "" {
+		return false
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return true
+		} else if err != nil {
+			return false
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return false
+		}
+		writeField(h, []byte(part.FormName()))
+		writeField(h, []byte(part.FileName()))
+		writeField(h, content)
+	}
+}
+
+// writeField writes a length-prefixed value so that adjacent fields cannot
+// run into each other
+func writeField(h hash.Hash, value []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(value)))
+	h.Write(length[:])
+	h.Write(value)
+}
+
+// capturingWriter keeps a copy of the response body
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
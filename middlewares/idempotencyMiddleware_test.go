@@ -0,0 +1,157 @@
+package middlewares
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/database"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// multipartBody builds a multipart body with one field and one file, using
+// the given boundary
+func multipartBody(t *testing.T, boundary, field, content string) ([]byte, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.SetBoundary(boundary); err != nil {
+		t.Fatal(err)
+	}
+	w.WriteField("position", field)
+	part, _ := w.CreateFormFile("images", "car.jpg")
+	part.Write([]byte(content))
+	w.Close()
+	return buf.Bytes(), w.FormDataContentType()
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	fingerprint := func(method, target, contentType string, body []byte) string {
+		r := httptest.NewRequest(method, target, nil)
+		r.Header.Set("Content-Type", contentType)
+		return requestFingerprint(r, 0, body)
+	}
+	inOrganization := func(id uint) string {
+		r := httptest.NewRequest("POST", "/api/cars", nil)
+		r.Header.Set("Content-Type", "application/json")
+		return requestFingerprint(r, id, []byte(`{"title":"a"}`))
+	}
+
+	base := fingerprint("POST", "/api/cars", "application/json", []byte(`{"title":"a"}`))
+	multipartA, typeA := multipartBody(t, "boundaryA", "1", "jpeg")
+	multipartB, typeB := multipartBody(t, "boundaryB", "1", "jpeg")
+	multipartC, typeC := multipartBody(t, "boundaryC", "1", "png")
+	split, _ := multipartBody(t, "boundaryA", "1j", "peg")
+
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{"same request", base, fingerprint("POST", "/api/cars", "application/json", []byte(`{"title":"a"}`)), true},
+		{"content type parameters", base, fingerprint("POST", "/api/cars", "application/json; charset=utf-8", []byte(`{"title":"a"}`)), true},
+		{"other body", base, fingerprint("POST", "/api/cars", "application/json", []byte(`{"title":"b"}`)), false},
+		{"other path", base, fingerprint("POST", "/api/cars/1", "application/json", []byte(`{"title":"a"}`)), false},
+		{"other query", base, fingerprint("POST", "/api/cars?x=1", "application/json", []byte(`{"title":"a"}`)), false},
+		{"other method", base, fingerprint("PUT", "/api/cars", "application/json", []byte(`{"title":"a"}`)), false},
+		{"other organization", base, inOrganization(1), false},
+		{"same organization", inOrganization(1), inOrganization(1), true},
+		{"other content type", base, fingerprint("POST", "/api/cars", "text/plain", []byte(`{"title":"a"}`)), false},
+		{"new multipart boundary", fingerprint("POST", "/i", typeA, multipartA), fingerprint("POST", "/i", typeB, multipartB), true},
+		{"other multipart file", fingerprint("POST", "/i", typeA, multipartA), fingerprint("POST", "/i", typeC, multipartC), false},
+		{"multipart fields do not run together", fingerprint("POST", "/i", typeA, multipartA), fingerprint("POST", "/i", typeA, split), false},
+		{"invalid multipart is hashed whole", fingerprint("POST", "/i", typeA, []byte("garbage")), fingerprint("POST", "/i", typeB, []byte("garbage")), true},
+		{"other invalid multipart", fingerprint("POST", "/i", typeA, []byte("garbage")), fingerprint("POST", "/i", typeA, []byte("rubbish")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.a == tt.b) != tt.equal {
+				t.Errorf("fingerprints equal = %v, want %v", tt.a == tt.b, tt.equal)
+			}
+		})
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	db, err := database.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := models.User{Username: "alice", Email: "alice@example.com", Password: "x"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	status := http.StatusCreated
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user", user) })
+	router.Use(Idempotency(db, config.Config{IdempotencyKeyTTL: time.Hour}))
+	router.POST("/cars", func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/cars/1")
+		c.JSON(status, gin.H{"calls": calls})
+	})
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/cars", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		body     string
+		status   int
+		calls    int
+		replayed bool
+	}{
+		{"first request", "k1", `{"a":1}`, http.StatusCreated, 1, false},
+		{"retry", "k1", `{"a":1}`, http.StatusCreated, 1, true},
+		{"key reused for another body", "k1", `{"a":2}`, http.StatusUnprocessableEntity, 1, false},
+		{"new key", "k2", `{"a":2}`, http.StatusCreated, 2, false},
+		{"no key", "", `{"a":1}`, http.StatusCreated, 3, false},
+		{"key too long", strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`, http.StatusBadRequest, 3, false},
+	}
+	for _, tt := range tests {
+		w := send(tt.key, tt.body)
+		if w.Code != tt.status || calls != tt.calls {
+			t.Fatalf("%s: status %d after %d calls, want %d after %d: %s", tt.name, w.Code, calls, tt.status, tt.calls, w.Body)
+		}
+		if tt.status == http.StatusUnprocessableEntity && !strings.Contains(w.Body.String(), "idempotency_key_reused") {
+			t.Errorf("%s: body %s, want the idempotency_key_reused code", tt.name, w.Body)
+		}
+		if replayed := w.Header().Get("Idempotent-Replayed") == "true"; replayed != tt.replayed {
+			t.Errorf("%s: replayed = %v, want %v", tt.name, replayed, tt.replayed)
+		}
+		if tt.replayed && (w.Header().Get("Location") != "/cars/1" || w.Body.String() != `{"calls":1}`) {
+			t.Errorf("%s: replayed Location %q and body %s, want the original", tt.name, w.Header().Get("Location"), w.Body)
+		}
+	}
+
+	// Server errors release the key so the request can be retried
+	status = http.StatusInternalServerError
+	if w := send("k3", `{}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	status = http.StatusCreated
+	if w := send("k3", `{}`); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" || calls != 5 {
+		t.Errorf("retry after a server error: status %d after %d calls, want a fresh 201", w.Code, calls)
+	}
+}
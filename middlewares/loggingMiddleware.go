@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// Logger logs one structured record per request and gives handlers a
+// logger tagged with the request ID through the request context. Must run
+// after RequestID.
+func Logger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := logging.WithLogger(c.Request.Context(), logger.With("request_id", c.GetString("request_id")))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", logging.Milliseconds(time.Since(start)),
+			"client_ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		}
+		if errs := c.Errors.Errors(); len(errs) > 0 {
+			attrs = append(attrs, "error", strings.Join(errs, "; "))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		// Later middlewares may have added attributes, such as the user ID
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the duration of every request by route template, so that
+// /api/cars/1 and /api/cars/2 share one series. Requests that match no route
+// are grouped under "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsToken requires "Authorization: Bearer <token>" when token is set,
+// so that metrics can be scraped without exposing them publicly
+func MetricsToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			apierrors.RespondCode(c, http.StatusUnauthorized, apierrors.CodeInvalidToken, "Invalid metrics token")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ActiveOrganization selects the organization the request acts in: the one
+// named by the X-Organization-ID header, or else the access token's
+// organization claim. A header value of 0 selects the user's personal cars
+// regardless of the claim. The user's membership is checked on every
+// request and stored as "membership"; requests without an organization have
+// none. Must run after AuthMiddleware.
+func ActiveOrganization(orgs repositories.OrganizationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(models.User)
+		claims := c.MustGet("claims").(utils.Claims)
+
+		orgID := claims.OrganizationID
+		if header := c.GetHeader("X-Organization-ID"); header != "" {
+			id, err := strconv.ParseUint(header, 10, 0)
+			if err != nil {
+				apierrors.Respond(c, http.StatusBadRequest, "Invalid X-Organization-ID header")
+				return
+			}
+			orgID = uint(id)
+		}
+		if orgID == 0 {
+			c.Next()
+			return
+		}
+
+		member, err := orgs.FindMember(c.Request.Context(), orgID, user.ID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			apierrors.Respond(c, http.StatusForbidden, "You are not a member of this organization")
+			return
+		} else if err != nil {
+			apierrors.Internal(c, err, "Failed to fetch organization")
+			return
+		}
+
+		c.Set("membership", member)
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// maxRateLimitBodySize bounds the request bodies read to find the account
+// a request is for
+const maxRateLimitBodySize = 64 << 10
+
+// RateLimit limits requests with a token bucket per key, as returned by
+// key, in the bucket group name. Requests over the limit get 429 with a
+// Retry-After header. Requests without a key, and all requests when the
+// limit is zero or store is nil, are not limited. If the store fails the
+// request is let through rather than locking everyone out.
+func RateLimit(store ratelimit.Store, name string, limit config.RateLimit, key func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store == nil || limit.Requests <= 0 {
+			c.Next()
+			return
+		}
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), name+":"+k, limit)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Rate limit store failed", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			apierrors.Respond(c, http.StatusTooManyRequests, "Too many requests, try again later")
+			return
+		}
+		c.Next()
+	}
+}
+
+// ByIP keys rate limits by client IP. Forwarded addresses are only used
+// from TRUSTED_PROXIES.
+func ByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// ByUser keys rate limits by the authenticated user. Must run after
+// AuthMiddleware.
+func ByUser(c *gin.Context) string {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		return ""
+	}
+	return strconv.FormatUint(uint64(userInterface.(models.User).ID), 10)
+}
+
+// ByEmail keys rate limits by the "email" field of a JSON body, so that an
+// account is protected however many addresses the requests come from. The
+// body is left for the handler to read.
+func ByEmail(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRateLimitBodySize))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil {
+		return ""
+	}
+
+	var input struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(body, &input) != nil {
+		return ""
+	}
+	return models.NormalizeEmail(input.Email)
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panic in a handler into a 500 error response, logging
+// the panic with its stack trace. If the handler already started writing
+// the response it is left as it is.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				// ErrAbortHandler asks the server to drop the connection
+				// quietly
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				logging.FromContext(c.Request.Context()).Error("Panic serving request",
+					"panic", fmt.Sprint(err), "stack", string(debug.Stack()))
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				apierrors.Respond(c, http.StatusInternalServerError, "Internal server error")
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/akashkumar7902/car-management-backend/apierrors"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows the request through only if the authenticated user has
+// one of the given roles. Must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userInterface, exists := c.Get("user")
+		if !exists {
+			apierrors.Respond(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		user := userInterface.(models.User)
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		apierrors.Respond(c, http.StatusForbidden, "Insufficient permissions")
+	}
+}
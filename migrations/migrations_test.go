@@ -0,0 +1,88 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// migrationFS returns a file system with the given files in its sql
+// directory, each containing its own name
+func migrationFS(names ...string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, name := range names {
+		fsys["sql/"+name] = &fstest.MapFile{Data: []byte("-- " + name)}
+	}
+	return fsys
+}
+
+func TestLoadOrdersByVersion(t *testing.T) {
+	// Versions compare as numbers, not as strings
+	migrations, err := load(migrationFS(
+		"10_ten.up.sql", "10_ten.down.sql",
+		"0002_two.down.sql", "0002_two.up.sql",
+		"9_nine.up.sql", "9_nine.down.sql",
+		"0001_one_with_underscores.up.sql", "0001_one_with_underscores.down.sql",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		version int64
+		name    string
+	}{
+		{1, "one_with_underscores"},
+		{2, "two"},
+		{9, "nine"},
+		{10, "ten"},
+	}
+	if len(migrations) != len(want) {
+		t.Fatalf("loaded %d migrations, want %d", len(migrations), len(want))
+	}
+	for i, w := range want {
+		m := migrations[i]
+		if m.Version != w.version || m.Name != w.name {
+			t.Errorf("migration %d = %d %s, want %d %s", i, m.Version, m.Name, w.version, w.name)
+		}
+		if !strings.HasSuffix(m.Up, ".up.sql") || !strings.HasSuffix(m.Down, ".down.sql") {
+			t.Errorf("migration %d has up %q and down %q", m.Version, m.Up, m.Down)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		err   string
+	}{
+		{"unknown suffix", []string{"0001_one.sql"}, "expected .up.sql or .down.sql"},
+		{"no description", []string{"0001.up.sql", "0001.down.sql"}, "expected NNNN_description"},
+		{"invalid version", []string{"one_first.up.sql", "one_first.down.sql"}, "invalid version"},
+		{"conflicting names", []string{"0001_one.up.sql", "0001_uno.down.sql"}, "conflicting names"},
+		{"missing down", []string{"0001_one.up.sql"}, "both up and down"},
+		{"missing up", []string{"0001_one.down.sql"}, "both up and down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(migrationFS(tt.files...))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("load = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadEmbedded(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The embedded migrations are numbered without gaps
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Fatalf("migration %d has version %d, want %d", i, m.Version, i+1)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+-- Baseline schema. Databases created before versioned migrations already
+-- have these tables (from GORM AutoMigrate), hence IF NOT EXISTS.
+CREATE TABLE IF NOT EXISTS users (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    username   text NOT NULL UNIQUE,
+    email      text NOT NULL UNIQUE,
+    password   text NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS cars (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    user_id     bigint REFERENCES users (id),
+    title       text NOT NULL,
+    description text,
+    tags        text[],
+    images      text[]
+);
+CREATE INDEX IF NOT EXISTS idx_cars_deleted_at ON cars (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_cars_user_id ON cars (user_id);
@@ -0,0 +1,19 @@
+-- Before versioned migrations the sessions table was created by hand or
+-- with AutoMigrate, hence IF NOT EXISTS.
+CREATE TABLE IF NOT EXISTS sessions (
+    id                  bigserial PRIMARY KEY,
+    created_at          timestamptz,
+    updated_at          timestamptz,
+    deleted_at          timestamptz,
+    user_id             bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    refresh_token_hash  text NOT NULL,
+    previous_token_hash text NOT NULL DEFAULT '',
+    user_agent          text NOT NULL DEFAULT '',
+    ip_address          text NOT NULL DEFAULT '',
+    expires_at          timestamptz NOT NULL,
+    revoked_at          timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_refresh_token_hash ON sessions (refresh_token_hash);
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_token_hash ON sessions (previous_token_hash);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_deleted_at ON sessions (deleted_at);
@@ -0,0 +1,15 @@
+CREATE TABLE user_tokens (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id    bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    text NOT NULL,
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz
+);
+CREATE UNIQUE INDEX idx_user_tokens_token_hash ON user_tokens (token_hash);
+CREATE INDEX idx_user_tokens_user_id ON user_tokens (user_id);
+CREATE INDEX idx_user_tokens_purpose ON user_tokens (purpose);
+CREATE INDEX idx_user_tokens_deleted_at ON user_tokens (deleted_at);
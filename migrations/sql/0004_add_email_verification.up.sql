@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN verified    boolean NOT NULL DEFAULT false,
+    ADD COLUMN verified_at timestamptz;
+
+-- Accounts created before verification existed are trusted as-is so that
+-- enabling REQUIRE_EMAIL_VERIFICATION does not lock them out
+UPDATE users SET verified = true, verified_at = now();
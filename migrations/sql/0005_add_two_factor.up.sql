@@ -0,0 +1,17 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret       text NOT NULL DEFAULT '',
+    ADD COLUMN totp_enabled      boolean NOT NULL DEFAULT false,
+    ADD COLUMN totp_last_counter bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_codes (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id    bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  text NOT NULL,
+    used_at    timestamptz
+);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
+CREATE INDEX idx_recovery_codes_code_hash ON recovery_codes (code_hash);
+CREATE INDEX idx_recovery_codes_deleted_at ON recovery_codes (deleted_at);
@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN role        text NOT NULL DEFAULT 'user',
+    ADD COLUMN disabled_at timestamptz;
+
+ALTER TABLE users
+    ADD CONSTRAINT chk_users_role CHECK (role IN ('user', 'support', 'admin'));
+
+CREATE INDEX idx_users_role ON users (role);
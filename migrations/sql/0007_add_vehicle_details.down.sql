@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_cars_user_registration_plate;
+DROP INDEX IF EXISTS idx_cars_user_vin;
+
+ALTER TABLE cars
+    DROP COLUMN IF EXISTS vin,
+    DROP COLUMN IF EXISTS registration_plate,
+    DROP COLUMN IF EXISTS colour,
+    DROP COLUMN IF EXISTS mileage,
+    DROP COLUMN IF EXISTS transmission,
+    DROP COLUMN IF EXISTS fuel_type,
+    DROP COLUMN IF EXISTS body_type,
+    DROP COLUMN IF EXISTS "trim",
+    DROP COLUMN IF EXISTS year,
+    DROP COLUMN IF EXISTS model,
+    DROP COLUMN IF EXISTS make;
@@ -0,0 +1,18 @@
+ALTER TABLE cars
+    ADD COLUMN make               text NOT NULL DEFAULT '',
+    ADD COLUMN model              text NOT NULL DEFAULT '',
+    ADD COLUMN year               integer,
+    ADD COLUMN "trim"             text NOT NULL DEFAULT '',
+    ADD COLUMN body_type          text NOT NULL DEFAULT '',
+    ADD COLUMN fuel_type          text NOT NULL DEFAULT '',
+    ADD COLUMN transmission       text NOT NULL DEFAULT '',
+    ADD COLUMN mileage            integer CHECK (mileage >= 0),
+    ADD COLUMN colour             text NOT NULL DEFAULT '',
+    ADD COLUMN registration_plate text NOT NULL DEFAULT '',
+    ADD COLUMN vin                text NOT NULL DEFAULT '';
+
+-- VIN and plate are unique per owner among cars that have not been deleted
+CREATE UNIQUE INDEX idx_cars_user_vin ON cars (user_id, vin)
+    WHERE vin <> '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_cars_user_registration_plate ON cars (user_id, registration_plate)
+    WHERE registration_plate <> '' AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_cars_search_vector;
+ALTER TABLE cars DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS car_tags_text(text[]);
@@ -0,0 +1,17 @@
+-- Full-text search for cars
+
+-- array_to_string is only STABLE, so wrap it to use it in a generated column
+CREATE OR REPLACE FUNCTION car_tags_text(tags text[]) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$ SELECT coalesce(array_to_string(tags, ' '), '') $$;
+
+ALTER TABLE cars ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', car_tags_text(tags)), 'A') ||
+        setweight(to_tsvector('english', coalesce(make, '') || ' ' || coalesce(model, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C') ||
+        setweight(to_tsvector('english', coalesce("trim", '') || ' ' || coalesce(colour, '')), 'D')
+    ) STORED;
+
+CREATE INDEX idx_cars_search_vector ON cars USING GIN (search_vector);
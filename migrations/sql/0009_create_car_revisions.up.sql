@@ -0,0 +1,35 @@
+CREATE TABLE car_revisions (
+    id         bigserial PRIMARY KEY,
+    car_id     bigint NOT NULL,
+    user_id    bigint NOT NULL,
+    action     text NOT NULL CHECK (action IN ('snapshot', 'create', 'update', 'delete', 'restore', 'revert')),
+    changes    jsonb NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- History outlives the car itself, so there is no foreign key to cars
+CREATE INDEX idx_car_revisions_car_id ON car_revisions (car_id, id);
+
+-- Record the current state of existing cars as the starting point of their
+-- history
+INSERT INTO car_revisions (car_id, user_id, action, changes, created_at)
+SELECT id, user_id, 'snapshot',
+       jsonb_build_object(
+           'title',              jsonb_build_object('old', NULL, 'new', title),
+           'description',        jsonb_build_object('old', NULL, 'new', coalesce(description, '')),
+           'tags',               jsonb_build_object('old', NULL, 'new', to_jsonb(coalesce(tags, '{}'))),
+           'images',             jsonb_build_object('old', NULL, 'new', to_jsonb(coalesce(images, '{}'))),
+           'make',               jsonb_build_object('old', NULL, 'new', make),
+           'model',              jsonb_build_object('old', NULL, 'new', model),
+           'year',               jsonb_build_object('old', NULL, 'new', year),
+           'trim',               jsonb_build_object('old', NULL, 'new', "trim"),
+           'body_type',          jsonb_build_object('old', NULL, 'new', body_type),
+           'fuel_type',          jsonb_build_object('old', NULL, 'new', fuel_type),
+           'transmission',       jsonb_build_object('old', NULL, 'new', transmission),
+           'mileage',            jsonb_build_object('old', NULL, 'new', mileage),
+           'colour',             jsonb_build_object('old', NULL, 'new', colour),
+           'registration_plate', jsonb_build_object('old', NULL, 'new', registration_plate),
+           'vin',                jsonb_build_object('old', NULL, 'new', vin)
+       ),
+       now()
+FROM cars;
@@ -0,0 +1 @@
+ALTER TABLE cars ADD COLUMN version integer NOT NULL DEFAULT 1;
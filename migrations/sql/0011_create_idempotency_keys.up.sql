@@ -0,0 +1,13 @@
+CREATE TABLE idempotency_keys (
+    id          bigserial PRIMARY KEY,
+    user_id     bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key         text NOT NULL,
+    fingerprint text NOT NULL,
+    status_code integer NOT NULL DEFAULT 0,
+    header      jsonb NOT NULL DEFAULT '{}',
+    body        bytea,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    expires_at  timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX idx_idempotency_keys_user_key ON idempotency_keys (user_id, key);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,12 @@
+CREATE TABLE car_shares (
+    id          bigserial PRIMARY KEY,
+    car_id      bigint NOT NULL REFERENCES cars (id) ON DELETE CASCADE,
+    user_id     bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    invited_by  bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role        text NOT NULL CHECK (role IN ('viewer', 'editor')),
+    accepted_at timestamptz,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_car_shares_car_user ON car_shares (car_id, user_id);
+CREATE INDEX idx_car_shares_user_id ON car_shares (user_id);
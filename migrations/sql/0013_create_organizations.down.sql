@@ -0,0 +1,17 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS organization_id;
+
+DROP INDEX IF EXISTS idx_cars_organization_vin;
+DROP INDEX IF EXISTS idx_cars_organization_registration_plate;
+DROP INDEX IF EXISTS idx_cars_user_vin;
+DROP INDEX IF EXISTS idx_cars_user_registration_plate;
+-- Fleet cars become personal cars of the users who added them, which fails
+-- if that gives a user two cars with the same VIN or plate
+ALTER TABLE cars DROP COLUMN IF EXISTS organization_id;
+CREATE UNIQUE INDEX idx_cars_user_vin ON cars (user_id, vin)
+    WHERE vin <> '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_cars_user_registration_plate ON cars (user_id, registration_plate)
+    WHERE registration_plate <> '' AND deleted_at IS NULL;
+
+DROP TABLE IF EXISTS organization_invitations;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
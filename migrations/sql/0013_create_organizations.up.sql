@@ -0,0 +1,49 @@
+CREATE TABLE organizations (
+    id         bigserial PRIMARY KEY,
+    name       text NOT NULL,
+    created_by bigint NOT NULL REFERENCES users (id),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE organization_members (
+    id              bigserial PRIMARY KEY,
+    organization_id bigint NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id         bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role            text NOT NULL CHECK (role IN ('owner', 'manager', 'driver')),
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    updated_at      timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_organization_members_org_user ON organization_members (organization_id, user_id);
+CREATE INDEX idx_organization_members_user_id ON organization_members (user_id);
+
+CREATE TABLE organization_invitations (
+    id              bigserial PRIMARY KEY,
+    organization_id bigint NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email           text NOT NULL,
+    role            text NOT NULL CHECK (role IN ('owner', 'manager', 'driver')),
+    invited_by      bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at      timestamptz NOT NULL,
+    created_at      timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_organization_invitations_organization_id ON organization_invitations (organization_id);
+CREATE INDEX idx_organization_invitations_email ON organization_invitations (email);
+
+-- Organizations with cars cannot be deleted
+ALTER TABLE cars ADD COLUMN organization_id bigint REFERENCES organizations (id);
+CREATE INDEX idx_cars_organization_id ON cars (organization_id);
+
+-- VIN and plate are unique per owner: the user for personal cars, the
+-- organization for fleet cars
+DROP INDEX idx_cars_user_vin;
+DROP INDEX idx_cars_user_registration_plate;
+CREATE UNIQUE INDEX idx_cars_user_vin ON cars (user_id, vin)
+    WHERE vin <> '' AND deleted_at IS NULL AND organization_id IS NULL;
+CREATE UNIQUE INDEX idx_cars_user_registration_plate ON cars (user_id, registration_plate)
+    WHERE registration_plate <> '' AND deleted_at IS NULL AND organization_id IS NULL;
+CREATE UNIQUE INDEX idx_cars_organization_vin ON cars (organization_id, vin)
+    WHERE vin <> '' AND deleted_at IS NULL AND organization_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_cars_organization_registration_plate ON cars (organization_id, registration_plate)
+    WHERE registration_plate <> '' AND deleted_at IS NULL AND organization_id IS NOT NULL;
+
+ALTER TABLE sessions ADD COLUMN organization_id bigint REFERENCES organizations (id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_cars_public_slug;
+ALTER TABLE cars DROP COLUMN IF EXISTS published_at;
+ALTER TABLE cars DROP COLUMN IF EXISTS public_slug;
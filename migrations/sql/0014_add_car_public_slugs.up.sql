@@ -0,0 +1,3 @@
+ALTER TABLE cars ADD COLUMN public_slug text;
+ALTER TABLE cars ADD COLUMN published_at timestamptz;
+CREATE UNIQUE INDEX idx_cars_public_slug ON cars (public_slug);
@@ -0,0 +1,6 @@
+UPDATE car_shares SET user_id = users.id FROM users WHERE car_shares.user_id IS NULL AND lower(users.email) = car_shares.email;
+DELETE FROM car_shares WHERE user_id IS NULL;
+ALTER TABLE car_shares ALTER COLUMN user_id SET NOT NULL;
+DROP INDEX idx_car_shares_email;
+DROP INDEX idx_car_shares_car_email;
+ALTER TABLE car_shares DROP COLUMN email;
@@ -0,0 +1,9 @@
+-- Shares are sent to an email address and only linked to a user once
+-- accepted, so that sharing does not reveal which addresses have accounts
+ALTER TABLE car_shares ADD COLUMN email text;
+UPDATE car_shares SET email = lower(users.email) FROM users WHERE users.id = car_shares.user_id;
+ALTER TABLE car_shares ALTER COLUMN email SET NOT NULL;
+ALTER TABLE car_shares ALTER COLUMN user_id DROP NOT NULL;
+UPDATE car_shares SET user_id = NULL WHERE accepted_at IS NULL;
+CREATE UNIQUE INDEX idx_car_shares_car_email ON car_shares (car_id, email);
+CREATE INDEX idx_car_shares_email ON car_shares (email);
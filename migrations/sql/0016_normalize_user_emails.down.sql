@@ -0,0 +1,2 @@
+-- The original casing of the emails is not kept, so there is nothing to undo
+SELECT 1;
@@ -0,0 +1,19 @@
+-- Emails are now stored lowercased and trimmed. Accounts whose addresses
+-- differ only in case or spacing would clash once normalized and must be
+-- merged by hand first, so the migration stops and lists them.
+DO $$
+DECLARE
+  conflicts text;
+BEGIN
+  SELECT string_agg(emails, '; ') INTO conflicts FROM (
+    SELECT string_agg(email, ', ' ORDER BY id) AS emails
+    FROM users
+    GROUP BY lower(trim(email))
+    HAVING count(*) > 1
+  ) clashes;
+  IF conflicts IS NOT NULL THEN
+    RAISE EXCEPTION 'users share an email once it is lowercased and trimmed, merge these accounts before migrating: %', conflicts;
+  END IF;
+END $$;
+
+UPDATE users SET email = lower(trim(email)) WHERE email <> lower(trim(email));
@@ -0,0 +1,5 @@
+-- Sessions can no longer be refreshed forever. Existing sessions get the
+-- default SESSION_MAX_AGE of 90 days from when they were created.
+ALTER TABLE sessions ADD COLUMN max_expires_at timestamptz;
+UPDATE sessions SET max_expires_at = COALESCE(created_at, now()) + interval '90 days';
+ALTER TABLE sessions ALTER COLUMN max_expires_at SET NOT NULL;
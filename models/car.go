@@ -1,15 +1,171 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/utils"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
+var (
+	BodyTypes     = []string{"sedan", "hatchback", "suv", "coupe", "convertible", "wagon", "van", "minivan", "pickup", "other"}
+	FuelTypes     = []string{"petrol", "diesel", "electric", "hybrid", "plugin_hybrid", "cng", "lpg", "hydrogen", "other"}
+	Transmissions = []string{"manual", "automatic", "cvt", "dct", "other"}
+)
+
+// minCarYear is the year of the first production automobile
+const minCarYear = 1886
+
 type Car struct {
 	gorm.Model
-	UserID      uint           `json:"user_id"`
-	Title       string         `gorm:"not null" json:"title"`
-	Description string         `json:"description"`
-	Tags        pq.StringArray `gorm:"type:text[]" json:"tags"`
-	Images      pq.StringArray `gorm:"type:text[]" json:"images"` // URLs
+	UserID uint `json:"user_id"`
+	// OrganizationID is set for fleet cars, which belong to the
+	// organization rather than to the user who added them
+	OrganizationID *uint          `gorm:"index" json:"organization_id"`
+	Title          string         `gorm:"not null" json:"title"`
+	Description    string         `json:"description"`
+	Tags           pq.StringArray `gorm:"type:text[]" json:"tags"`
+	Images         pq.StringArray `gorm:"type:text[]" json:"images"` // URLs
+
+	// Vehicle details
+	Make              string `json:"make"`
+	ModelName         string `gorm:"column:model" json:"model"`
+	Year              *int   `json:"year"`
+	Trim              string `json:"trim"`
+	BodyType          string `json:"body_type"`
+	FuelType          string `json:"fuel_type"`
+	Transmission      string `json:"transmission"`
+	Mileage           *int   `json:"mileage"`
+	Colour            string `json:"colour"`
+	RegistrationPlate string `json:"registration_plate"`
+	VIN               string `gorm:"column:vin" json:"vin"`
+
+	// PublicSlug is set while the car is published and names its public,
+	// read-only page
+	PublicSlug  *string    `gorm:"uniqueIndex" json:"public_slug"`
+	PublishedAt *time.Time `json:"published_at"`
+
+	// Version is incremented on every change, for optimistic locking
+	Version int `gorm:"not null;default:1" json:"version"`
+}
+
+// IsPublished reports whether the car has a public page
+func (c *Car) IsPublished() bool {
+	return c.PublicSlug != nil
+}
+
+// PublicCar is the part of a published car that anyone can see. It leaves
+// out the owner and anything that identifies the vehicle itself, such as
+// the VIN and registration plate.
+type PublicCar struct {
+	Slug         string     `json:"slug"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Tags         []string   `json:"tags"`
+	Images       []string   `json:"images"`
+	Make         string     `json:"make"`
+	ModelName    string     `json:"model"`
+	Year         *int       `json:"year"`
+	Trim         string     `json:"trim"`
+	BodyType     string     `json:"body_type"`
+	FuelType     string     `json:"fuel_type"`
+	Transmission string     `json:"transmission"`
+	Mileage      *int       `json:"mileage"`
+	Colour       string     `json:"colour"`
+	PublishedAt  *time.Time `json:"published_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Public returns the publicly visible fields of a published car
+func (c *Car) Public() PublicCar {
+	public := PublicCar{
+		Title:        c.Title,
+		Description:  c.Description,
+		Tags:         append([]string{}, c.Tags...),
+		Images:       append([]string{}, c.Images...),
+		Make:         c.Make,
+		ModelName:    c.ModelName,
+		Year:         c.Year,
+		Trim:         c.Trim,
+		BodyType:     c.BodyType,
+		FuelType:     c.FuelType,
+		Transmission: c.Transmission,
+		Mileage:      c.Mileage,
+		Colour:       c.Colour,
+		PublishedAt:  c.PublishedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+	if c.PublicSlug != nil {
+		public.Slug = *c.PublicSlug
+	}
+	return public
+}
+
+// Normalize canonicalises free-form vehicle fields so that validation and
+// uniqueness checks are not defeated by case or spacing differences
+func (c *Car) Normalize() {
+	c.Title = strings.TrimSpace(c.Title)
+	c.Make = strings.TrimSpace(c.Make)
+	c.ModelName = strings.TrimSpace(c.ModelName)
+	c.Trim = strings.TrimSpace(c.Trim)
+	c.Colour = strings.TrimSpace(c.Colour)
+	c.BodyType = strings.ToLower(strings.TrimSpace(c.BodyType))
+	c.FuelType = strings.ToLower(strings.TrimSpace(c.FuelType))
+	c.Transmission = strings.ToLower(strings.TrimSpace(c.Transmission))
+	c.RegistrationPlate = strings.ToUpper(strings.Join(strings.Fields(c.RegistrationPlate), ""))
+	c.VIN = utils.NormalizeVIN(c.VIN)
+}
+
+// FieldError is a validation error about one field, named by its JSON key
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Validate checks the car's fields, returning a *FieldError for the first
+// invalid one. Call Normalize first.
+func (c *Car) Validate() error {
+	if c.Title == "" {
+		return &FieldError{"title", "Title is required"}
+	}
+	if c.Year != nil && (*c.Year < minCarYear || *c.Year > time.Now().Year()+1) {
+		return &FieldError{"year", fmt.Sprintf("Year must be between %d and %d", minCarYear, time.Now().Year()+1)}
+	}
+	if c.Mileage != nil && *c.Mileage < 0 {
+		return &FieldError{"mileage", "Mileage cannot be negative"}
+	}
+	if c.BodyType != "" && !contains(BodyTypes, c.BodyType) {
+		return &FieldError{"body_type", fmt.Sprintf("Body type must be one of: %s", strings.Join(BodyTypes, ", "))}
+	}
+	if c.FuelType != "" && !contains(FuelTypes, c.FuelType) {
+		return &FieldError{"fuel_type", fmt.Sprintf("Fuel type must be one of: %s", strings.Join(FuelTypes, ", "))}
+	}
+	if c.Transmission != "" && !contains(Transmissions, c.Transmission) {
+		return &FieldError{"transmission", fmt.Sprintf("Transmission must be one of: %s", strings.Join(Transmissions, ", "))}
+	}
+	if len(c.RegistrationPlate) > 15 {
+		return &FieldError{"registration_plate", "Registration plate is too long"}
+	}
+	if c.VIN != "" {
+		if err := utils.ValidateVIN(c.VIN); err != nil {
+			return &FieldError{"vin", err.Error()}
+		}
+	}
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
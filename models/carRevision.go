@@ -0,0 +1,162 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Revision actions
+const (
+	// RevisionSnapshot records the state of a car that existed before
+	// history was kept
+	RevisionSnapshot = "snapshot"
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionDelete   = "delete"
+	RevisionRestore  = "restore"
+	RevisionRevert   = "revert"
+)
+
+// FieldChange is the old and new value of a changed field
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// CarRevision is an append-only record of a change to a car. Changes are
+// keyed by the field's JSON name.
+type CarRevision struct {
+	ID        uint                   `gorm:"primaryKey" json:"id"`
+	CarID     uint                   `gorm:"not null;index" json:"car_id"`
+	UserID    uint                   `gorm:"not null" json:"user_id"`
+	Action    string                 `gorm:"not null" json:"action"`
+	Changes   map[string]FieldChange `gorm:"type:jsonb;not null;serializer:json" json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NewCarRevision records the change from before to after. Pass nil for
+// before when the car is new.
+func NewCarRevision(userID uint, action string, before, after *Car) CarRevision {
+	var old map[string]interface{}
+	if before != nil {
+		old = before.Fields()
+	}
+	changes := diffFields(old, after.Fields())
+
+	// Moving a car to the trash and back changes none of its fields
+	if before != nil && before.DeletedAt != after.DeletedAt {
+		changes["deleted_at"] = FieldChange{Old: deletedAtValue(before), New: deletedAtValue(after)}
+	}
+
+	return CarRevision{
+		CarID:   after.ID,
+		UserID:  userID,
+		Action:  action,
+		Changes: changes,
+	}
+}
+
+// Purged returns the car as it is after being deleted permanently, for
+// recording the deletion: none of its fields are left, and it counts as
+// deleted since it went to the trash, or since at if it never did
+func (c *Car) Purged(at time.Time) Car {
+	purged := Car{}
+	purged.ID = c.ID
+	purged.DeletedAt = c.DeletedAt
+	if !purged.DeletedAt.Valid {
+		purged.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	}
+	return purged
+}
+
+// Fields returns the car's user-editable fields keyed by JSON name
+func (c *Car) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"title":              c.Title,
+		"description":        c.Description,
+		"tags":               append([]string{}, c.Tags...),
+		"images":             append([]string{}, c.Images...),
+		"make":               c.Make,
+		"model":              c.ModelName,
+		"year":               intValue(c.Year),
+		"trim":               c.Trim,
+		"body_type":          c.BodyType,
+		"fuel_type":          c.FuelType,
+		"transmission":       c.Transmission,
+		"mileage":            intValue(c.Mileage),
+		"colour":             c.Colour,
+		"registration_plate": c.RegistrationPlate,
+		"vin":                c.VIN,
+	}
+}
+
+// SetFields overwrites the fields present in the map, which uses the same
+// keys and JSON-compatible values as Fields
+func (c *Car) SetFields(fields map[string]interface{}) error {
+	// Decoding writes through existing slices and pointers, which copies of
+	// the car may share, so drop them first
+	references := map[string]func(){
+		"tags":    func() { c.Tags = nil },
+		"images":  func() { c.Images = nil },
+		"year":    func() { c.Year = nil },
+		"mileage": func() { c.Mileage = nil },
+	}
+	for name, reset := range references {
+		if _, ok := fields[name]; ok {
+			reset()
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, c)
+}
+
+// ReplayCarRevisions returns the fields of a car as they were after the
+// last of the given revisions, which must be in order. Changes to
+// deleted_at are left out.
+func ReplayCarRevisions(revisions []CarRevision) map[string]interface{} {
+	fields := (&Car{}).Fields()
+	for _, revision := range revisions {
+		for name, change := range revision.Changes {
+			if _, ok := fields[name]; ok {
+				fields[name] = change.New
+			}
+		}
+	}
+	return fields
+}
+
+// diffFields compares two Fields maps. A nil before map counts every
+// non-empty field of after as changed.
+func diffFields(before, after map[string]interface{}) map[string]FieldChange {
+	if before == nil {
+		before = (&Car{}).Fields()
+	}
+	changes := map[string]FieldChange{}
+	for name, value := range after {
+		if !reflect.DeepEqual(before[name], value) {
+			changes[name] = FieldChange{Old: before[name], New: value}
+		}
+	}
+	return changes
+}
+
+func deletedAtValue(c *Car) interface{} {
+	if !c.DeletedAt.Valid {
+		return nil
+	}
+	return c.DeletedAt.Time
+}
+
+func intValue(p *int) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Share roles
+const (
+	// ShareViewer can see the car and its history
+	ShareViewer = "viewer"
+	// ShareEditor can also change the car's details and images
+	ShareEditor = "editor"
+)
+
+// ValidShareRole reports whether role is one of the share roles
+func ValidShareRole(role string) bool {
+	return role == ShareViewer || role == ShareEditor
+}
+
+// CarShare gives another user access to a car. It is sent to an email
+// address and takes effect once the user with that (verified) email
+// accepts it, which sets UserID.
+type CarShare struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	CarID      uint       `gorm:"not null;uniqueIndex:idx_car_shares_car_user;uniqueIndex:idx_car_shares_car_email" json:"car_id"`
+	Email      string     `gorm:"not null;uniqueIndex:idx_car_shares_car_email;index" json:"email"`
+	UserID     *uint      `gorm:"uniqueIndex:idx_car_shares_car_user;index" json:"user_id"`
+	InvitedBy  uint       `gorm:"not null" json:"invited_by"`
+	Role       string     `gorm:"not null" json:"role"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	User *User `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Car  *Car  `gorm:"constraint:OnDelete:CASCADE" json:"car,omitempty"`
+}
+
+// IsAccepted reports whether the invited user has accepted the share
+func (s *CarShare) IsAccepted() bool {
+	return s.AcceptedAt != nil
+}
+
+// IsFor reports whether the share has been accepted by the user
+func (s *CarShare) IsFor(userID uint) bool {
+	return s.UserID != nil && *s.UserID == userID
+}
+
+// CanEdit reports whether the share allows changing the car
+func (s *CarShare) CanEdit() bool {
+	return s.IsAccepted() && s.Role == ShareEditor
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey remembers a mutating request made with an Idempotency-Key
+// header so that retries get the original response instead of repeating the
+// work. StatusCode is zero while the first request is still running.
+type IdempotencyKey struct {
+	ID          uint              `gorm:"primaryKey"`
+	UserID      uint              `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	Key         string            `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	Fingerprint string            `gorm:"not null"`
+	StatusCode  int               `gorm:"not null;default:0"`
+	Header      map[string]string `gorm:"type:jsonb;not null;serializer:json"`
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+// IsComplete reports whether the original response has been stored
+func (k *IdempotencyKey) IsComplete() bool {
+	return k.StatusCode != 0
+}
+
+// DeleteExpiredIdempotencyKeys removes keys whose replay window is over and
+// returns how many were removed
+func DeleteExpiredIdempotencyKeys(db *gorm.DB) (int64, error) {
+	result := db.Where("expires_at < ?", time.Now()).Delete(&IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Organization roles
+const (
+	// OrgOwner manages the organization, its members and its cars
+	OrgOwner = "owner"
+	// OrgManager manages the organization's cars and invites members
+	OrgManager = "manager"
+	// OrgDriver can see and update the organization's cars
+	OrgDriver = "driver"
+)
+
+// ValidOrgRole reports whether role is one of the organization roles
+func ValidOrgRole(role string) bool {
+	switch role {
+	case OrgOwner, OrgManager, OrgDriver:
+		return true
+	}
+	return false
+}
+
+// Organization is a company or team that owns a fleet of cars
+type Organization struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedBy uint      `gorm:"not null" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrganizationMember gives a user a role in an organization
+type OrganizationMember struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"not null;uniqueIndex:idx_organization_members_org_user" json:"organization_id"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_organization_members_org_user;index" json:"user_id"`
+	Role           string    `gorm:"not null" json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	Organization *Organization `gorm:"constraint:OnDelete:CASCADE" json:"organization,omitempty"`
+	User         *User         `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// ManagesCars reports whether the member may add, delete and restore the
+// organization's cars
+func (m *OrganizationMember) ManagesCars() bool {
+	return m.Role == OrgOwner || m.Role == OrgManager
+}
+
+// CanInvite reports whether the member may invite others with the role.
+// Managers can invite managers and drivers; only owners can add owners.
+func (m *OrganizationMember) CanInvite(role string) bool {
+	switch m.Role {
+	case OrgOwner:
+		return true
+	case OrgManager:
+		return role != OrgOwner
+	}
+	return false
+}
+
+// OrganizationInvitation invites an email address to join an organization.
+// It is accepted by the user with that (verified) email.
+type OrganizationInvitation struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"not null;index" json:"organization_id"`
+	Email          string    `gorm:"not null;index" json:"email"`
+	Role           string    `gorm:"not null" json:"role"`
+	InvitedBy      uint      `gorm:"not null" json:"invited_by"`
+	ExpiresAt      time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	Organization *Organization `gorm:"constraint:OnDelete:CASCADE" json:"organization,omitempty"`
+}
+
+// IsExpired reports whether the invitation can no longer be accepted
+func (i *OrganizationInvitation) IsExpired() bool {
+	return !time.Now().Before(i.ExpiresAt)
+}
+
+// NormalizeEmail lowercases and trims an email address for comparison
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session is a server-side login session. Access tokens carry the session ID
+// and are only accepted while the session is active; the refresh token is
+// stored hashed and rotated on every use. Each refresh moves ExpiresAt
+// forward, but never past MaxExpiresAt, after which the user has to log in
+// again.
+type Session struct {
+	gorm.Model
+	UserID            uint       `gorm:"not null;index" json:"-"`
+	RefreshTokenHash  string     `gorm:"not null;uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"index" json:"-"`
+	UserAgent         string     `json:"user_agent"`
+	IPAddress         string     `json:"ip_address"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expires_at"`
+	MaxExpiresAt      time.Time  `gorm:"not null" json:"max_expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	// OrganizationID is the organization the session acts in, or nil for
+	// the user's personal cars. Access tokens carry it as a claim.
+	OrganizationID *uint `json:"organization_id,omitempty"`
+}
+
+// IsActive reports whether the session can still be used
+func (s *Session) IsActive() bool {
+	now := time.Now()
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt) && now.Before(s.MaxExpiresAt)
+}
+
+// RefreshedExpiry returns when the session expires if it is refreshed now
+// with the given refresh token lifetime
+func (s *Session) RefreshedExpiry(ttl time.Duration) time.Time {
+	expiresAt := time.Now().Add(ttl)
+	if expiresAt.After(s.MaxExpiresAt) {
+		return s.MaxExpiresAt
+	}
+	return expiresAt
+}
+
+// ActiveOrganizationID returns the session's organization ID, or 0
+func (s *Session) ActiveOrganizationID() uint {
+	if s.OrganizationID == nil {
+		return 0
+	}
+	return *s.OrganizationID
+}
+
+// RevokeUserSessions revokes every active session belonging to the user
+func RevokeUserSessions(db *gorm.DB, userID uint) error {
+	return db.Model(&Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -1,16 +1,53 @@
 package models
 
 import (
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+// ValidRole reports whether role is one of the known roles
+func ValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleSupport, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	gorm.Model `json:"-"`
-	Username   string `gorm:"unique;not null" json:"username"`
-	Email      string `gorm:"unique;not null" json:"email"`
-	Password   string `gorm:"not null" json:"-"`
-	Cars       []Car  `json:"cars"`
+	Username   string     `gorm:"unique;not null" json:"username"`
+	Email      string     `gorm:"unique;not null" json:"email"`
+	Password   string     `gorm:"not null" json:"-"`
+	Verified   bool       `gorm:"not null;default:false" json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	// TOTPSecret is set at enrollment and only trusted once TOTPEnabled
+	TOTPSecret      string     `json:"-"`
+	TOTPEnabled     bool       `gorm:"not null;default:false" json:"totp_enabled"`
+	TOTPLastCounter int64      `gorm:"not null;default:0" json:"-"`
+	Role            string     `gorm:"not null;default:user;index" json:"role"`
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"`
+	Cars            []Car      `json:"cars"`
+}
+
+// IsDisabled reports whether an administrator has disabled the account
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// BeforeSave stores the email normalized, so that addresses differing only
+// in case or surrounding spaces belong to one account
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	u.Email = NormalizeEmail(u.Email)
+	return nil
 }
 
 // HashPassword hashes the user's password before saving
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken is a single-use, expiring token sent to a user out of band
+// (e.g. by email). Only the SHA-256 hash of the token is stored.
+type UserToken struct {
+	gorm.Model
+	UserID    uint      `gorm:"not null;index"`
+	Purpose   string    `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+// IsUsable reports whether the token can still be redeemed
+func (t *UserToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
+
+// InvalidateUserTokens marks every outstanding token of the given purpose
+// for the user as used
+func InvalidateUserTokens(db *gorm.DB, userID uint, purpose string) error {
+	return db.Model(&UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+)
+
+// Lockout locks a key, such as an account, out for a growing period after
+// repeated failures. Once Threshold failures have been counted, each
+// further failure locks the key for Duration, doubled for every failure
+// past the threshold, up to MaxDuration.
+type Lockout struct {
+	Store       Store
+	Threshold   int
+	Duration    time.Duration
+	MaxDuration time.Duration
+	// Window is how long failures are remembered after the last one
+	Window time.Duration
+}
+
+// NewLockout returns the login lockout configured by the LOGIN_LOCKOUT_*
+// and LOGIN_FAILURE_WINDOW settings
+func NewLockout(store Store, cfg config.Config) *Lockout {
+	return &Lockout{
+		Store:       store,
+		Threshold:   cfg.LoginLockoutThreshold,
+		Duration:    cfg.LoginLockoutDuration,
+		MaxDuration: cfg.LoginLockoutMaxDuration,
+		Window:      cfg.LoginFailureWindow,
+	}
+}
+
+// Check returns how much longer the key is locked, or 0 if it is not
+func (l *Lockout) Check(ctx context.Context, key string) (time.Duration, error) {
+	until, err := l.Store.LockedUntil(ctx, key)
+	if err != nil || until.IsZero() {
+		return 0, err
+	}
+	return time.Until(until), nil
+}
+
+// Fail records a failure and returns the lock it caused, or 0 if none
+func (l *Lockout) Fail(ctx context.Context, key string) (time.Duration, error) {
+	if l.Threshold <= 0 {
+		return 0, nil
+	}
+	count, err := l.Store.AddFailure(ctx, key, l.Window)
+	if err != nil || count < l.Threshold {
+		return 0, err
+	}
+
+	lock := l.MaxDuration
+	if doublings := count - l.Threshold; doublings < 32 {
+		if d := l.Duration << doublings; d > 0 && d < lock {
+			lock = d
+		}
+	}
+	return lock, l.Store.Lock(ctx, key, time.Now().Add(lock))
+}
+
+// Succeed forgets the key's failures after a successful attempt
+func (l *Lockout) Succeed(ctx context.Context, key string) error {
+	return l.Store.Reset(ctx, key)
+}
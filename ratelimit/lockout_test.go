@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockoutEscalation(t *testing.T) {
+	ctx := context.Background()
+	l := &Lockout{
+		Store:       NewMemoryStore(),
+		Threshold:   3,
+		Duration:    time.Minute,
+		MaxDuration: 5 * time.Minute,
+		Window:      time.Hour,
+	}
+
+	// The lock caused by each failure in turn
+	want := []time.Duration{
+		0, 0,
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		5 * time.Minute,
+		5 * time.Minute,
+	}
+	for i, lock := range want {
+		got, err := l.Fail(ctx, "login:1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != lock {
+			t.Errorf("failure %d: lock = %v, want %v", i+1, got, lock)
+		}
+	}
+
+	// Far past the threshold the doubling must not overflow
+	for i := 0; i < 64; i++ {
+		if got, _ := l.Fail(ctx, "login:1"); got != l.MaxDuration {
+			t.Fatalf("failure %d: lock = %v, want %v", len(want)+i+1, got, l.MaxDuration)
+		}
+	}
+
+	wait, err := l.Check(ctx, "login:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait <= 4*time.Minute || wait > l.MaxDuration {
+		t.Errorf("Check = %v, want about %v", wait, l.MaxDuration)
+	}
+	if wait, _ := l.Check(ctx, "login:2"); wait != 0 {
+		t.Errorf("other key: Check = %v, want 0", wait)
+	}
+
+	if err := l.Succeed(ctx, "login:1"); err != nil {
+		t.Fatal(err)
+	}
+	if wait, _ := l.Check(ctx, "login:1"); wait != 0 {
+		t.Errorf("after Succeed: Check = %v, want 0", wait)
+	}
+	if lock, _ := l.Fail(ctx, "login:1"); lock != 0 {
+		t.Errorf("after Succeed: lock = %v, want the count to start over", lock)
+	}
+}
+
+func TestLockoutDisabled(t *testing.T) {
+	ctx := context.Background()
+	l := &Lockout{Store: NewMemoryStore(), Duration: time.Minute, MaxDuration: time.Hour, Window: time.Hour}
+	for i := 0; i < 10; i++ {
+		if lock, err := l.Fail(ctx, "login:1"); lock != 0 || err != nil {
+			t.Fatalf("Fail = %v, %v, want no lock without a threshold", lock, err)
+		}
+	}
+	if wait, _ := l.Check(ctx, "login:1"); wait != 0 {
+		t.Errorf("Check = %v, want 0", wait)
+	}
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+)
+
+// sweepInterval is how often MemoryStore drops entries that no longer
+// affect anything
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled completely, after which
+	// it is the same as a new one
+	full time.Time
+}
+
+type failures struct {
+	count   int
+	expires time.Time
+}
+
+// MemoryStore is a Store held in memory
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	failures  map[string]*failures
+	locks     map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  map[string]*bucket{},
+		failures: map[string]*failures{},
+		locks:    map[string]time.Time{},
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit config.RateLimit) (Result, error) {
+	if limit.Requests <= 0 || limit.Per <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	rate := capacity / limit.Per.Seconds() // tokens per second
+
+	b, ok := s.buckets[key]
+	if !ok || !now.Before(b.full) {
+		b = &bucket{tokens: capacity}
+		s.buckets[key] = b
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	}
+	b.updated = now
+
+	result := Result{Allowed: b.tokens >= 1}
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	result.Remaining = int(b.tokens)
+	b.full = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+	return result, nil
+}
+
+func (s *MemoryStore) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	f, ok := s.failures[key]
+	if !ok || !now.Before(f.expires) {
+		f = &failures{}
+		s.failures[key] = f
+	}
+	f.count++
+	f.expires = now.Add(window)
+	return f.count, nil
+}
+
+func (s *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locks[key] = until
+	return nil
+}
+
+func (s *MemoryStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until, ok := s.locks[key]
+	if !ok || !s.now().Before(until) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
+
+// sweep drops full buckets, forgotten failures and expired locks so that
+// memory does not grow with every client ever seen. Callers hold s.mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, f := range s.failures {
+		if !now.Before(f.expires) {
+			delete(s.failures, key)
+		}
+	}
+	for key, until := range s.locks {
+		if !now.Before(until) {
+			delete(s.locks, key)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+)
+
+// clock is a settable time source for MemoryStore
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.Now
+	return s, c
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStore()
+	limit := config.RateLimit{Requests: 3, Per: 3 * time.Second}
+
+	// Each step advances the clock, then takes a token
+	steps := []struct {
+		name       string
+		advance    time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{"first", 0, true, 2, 0},
+		{"second", 0, true, 1, 0},
+		{"third", 0, true, 0, 0},
+		{"bucket empty", 0, false, 0, time.Second},
+		{"half a token", 500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+		{"refilled one token", 500 * time.Millisecond, true, 0, 0},
+		{"refilled completely", time.Minute, true, 2, 0},
+	}
+	for _, step := range steps {
+		c.now = c.now.Add(step.advance)
+		result, err := s.Take(ctx, "ip:1", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Result{Allowed: step.allowed, Remaining: step.remaining, RetryAfter: step.retryAfter}
+		if result != want {
+			t.Errorf("%s: Take = %+v, want %+v", step.name, result, want)
+		}
+	}
+
+	// Keys have their own buckets
+	if result, _ := s.Take(ctx, "ip:2", limit); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("other key: Take = %+v, want a full bucket", result)
+	}
+
+	// A zero limit never limits
+	for i := 0; i < 10; i++ {
+		if result, _ := s.Take(ctx, "ip:1", config.RateLimit{}); !result.Allowed {
+			t.Fatalf("disabled limit: Take = %+v, want allowed", result)
+		}
+	}
+}
+
+func TestMemoryStoreFailures(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStore()
+	window := time.Hour
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		count   int
+	}{
+		{"first", 0, 1},
+		{"second", 0, 2},
+		{"within the window", 59 * time.Minute, 3},
+		{"window restarts at each failure", 59 * time.Minute, 4},
+		{"forgotten after the window", time.Hour, 1},
+	}
+	for _, step := range steps {
+		c.now = c.now.Add(step.advance)
+		count, err := s.AddFailure(ctx, "login:1", window)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != step.count {
+			t.Errorf("%s: AddFailure = %d, want %d", step.name, count, step.count)
+		}
+	}
+
+	if err := s.Reset(ctx, "login:1"); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := s.AddFailure(ctx, "login:1", window); count != 1 {
+		t.Errorf("after Reset: AddFailure = %d, want 1", count)
+	}
+}
+
+func TestMemoryStoreLocks(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStore()
+
+	until := c.now.Add(time.Minute)
+	if err := s.Lock(ctx, "login:1", until); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		advance time.Duration
+		want    time.Time
+	}{
+		{"locked", "login:1", 0, until},
+		{"other key", "login:2", 0, time.Time{}},
+		{"still locked", "login:1", 59 * time.Second, until},
+		{"expired", "login:1", time.Second, time.Time{}},
+	}
+	for _, tt := range tests {
+		c.now = c.now.Add(tt.advance)
+		got, err := s.LockedUntil(ctx, tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: LockedUntil = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	s.Lock(ctx, "login:1", c.now.Add(time.Minute))
+	s.Reset(ctx, "login:1")
+	if got, _ := s.LockedUntil(ctx, "login:1"); !got.IsZero() {
+		t.Errorf("after Reset: LockedUntil = %v, want unlocked", got)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStore()
+
+	s.Take(ctx, "ip:1", config.RateLimit{Requests: 1, Per: time.Second})
+	s.AddFailure(ctx, "login:1", time.Second)
+	s.Lock(ctx, "login:1", c.now.Add(time.Second))
+	s.AddFailure(ctx, "login:2", time.Hour)
+
+	c.now = c.now.Add(sweepInterval)
+	s.AddFailure(ctx, "login:3", time.Hour)
+
+	if len(s.buckets) != 0 || len(s.locks) != 0 {
+		t.Errorf("%d buckets and %d locks left, want none", len(s.buckets), len(s.locks))
+	}
+	if _, ok := s.failures["login:1"]; ok || len(s.failures) != 2 {
+		t.Errorf("failures = %v, want only login:2 and login:3", s.failures)
+	}
+}
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+)
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket
+	Remaining int
+	// RetryAfter is how long until the next token, when not allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps rate limiting state: token buckets, failure counts and
+// locks, each under its own key. MemoryStore keeps them in the process, so
+// every instance of the server counts separately; a shared store is needed
+// to limit across instances.
+type Store interface {
+	// Take takes a token from the key's bucket, which holds up to
+	// limit.Requests tokens and refills at limit.Requests per limit.Per
+	Take(ctx context.Context, key string, limit config.RateLimit) (Result, error)
+	// AddFailure counts a failure for the key and returns the count so far.
+	// The count is forgotten once window passes without failures.
+	AddFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	// Lock locks the key until the given time
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns when the key's lock ends, or the zero time if it
+	// is not locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset forgets the key's failures and lock
+	Reset(ctx context.Context, key string) error
+}
+
+// New returns the store selected by RATE_LIMIT_STORE
+func New(cfg config.Config) (Store, error) {
+	switch cfg.RateLimitStore {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
+	}
+}
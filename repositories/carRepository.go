@@ -0,0 +1,315 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict is returned by writes when the car has changed since
+// it was loaded
+var ErrVersionConflict = errors.New("car was modified concurrently")
+
+// ErrNoSearchTerms is returned by Search when the query has no letters or
+// digits to search for
+var ErrNoSearchTerms = errors.New("search query contains no terms")
+
+// CarSearchHit is a car matching a search. The highlights are HTML-escaped
+// with matches wrapped in <mark> tags.
+type CarSearchHit struct {
+	models.Car
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// CarRepository stores cars. Lookups by ID do not check ownership; that is
+// left to the caller. Writes to an existing car only succeed if its version
+// is still the one it was loaded with, and bump the version; otherwise they
+// return ErrVersionConflict.
+type CarRepository interface {
+	Create(ctx context.Context, car *models.Car) error
+	FindByID(ctx context.Context, id uint) (models.Car, error)
+	// FindByIDUnscoped also finds soft-deleted cars
+	FindByIDUnscoped(ctx context.Context, id uint) (models.Car, error)
+	// FindByOwner returns all of a user's cars ordered by ID
+	FindByOwner(ctx context.Context, userID uint) ([]models.Car, error)
+	Save(ctx context.Context, car *models.Car) error
+	UpdateImages(ctx context.Context, car *models.Car) error
+	// Delete soft-deletes the car
+	Delete(ctx context.Context, car *models.Car) error
+	// HardDelete removes the row permanently
+	HardDelete(ctx context.Context, car *models.Car) error
+	// Restore undoes a soft delete
+	Restore(ctx context.Context, car *models.Car) error
+	// SetPublicSlug publishes the car under slug, or unpublishes it if slug
+	// is nil. Changing the slug of a published car keeps its publish time.
+	SetPublicSlug(ctx context.Context, car *models.Car, slug *string) error
+	// FindByPublicSlug finds a published car that is not in the trash
+	FindByPublicSlug(ctx context.Context, slug string) (models.Car, error)
+	// ListDeletedByOwner returns a page of the user's soft-deleted personal
+	// cars, or the organization's if opts.OrganizationID is set, most
+	// recently deleted first. Only the paging and owner options are used.
+	ListDeletedByOwner(ctx context.Context, userID uint, opts CarListOptions) ([]models.Car, int64, error)
+	// FindDeletedBefore returns up to limit cars soft-deleted before cutoff
+	FindDeletedBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.Car, error)
+	// ListForUser returns a page of the cars in the user's opts.Scope. With
+	// ScopePublished the user is ignored.
+	ListForUser(ctx context.Context, userID uint, opts CarListOptions) ([]models.Car, int64, error)
+	// Search runs a full-text search over the cars in the user's
+	// opts.Scope. The query syntax is described on parseSearchQuery.
+	Search(ctx context.Context, userID uint, query string, opts CarListOptions) ([]CarSearchHit, int64, error)
+	// VehicleConflict returns the column ("vin" or "registration_plate")
+	// that another car of the same owner already uses, or "" if none. The
+	// owner is the organization for fleet cars and the user otherwise.
+	VehicleConflict(ctx context.Context, car models.Car) (string, error)
+}
+
+// carDialect holds the database-specific parts of the car queries
+type carDialect interface {
+	tagsAny(query *gorm.DB, tags []string) *gorm.DB
+	tagsAll(query *gorm.DB, tags []string) *gorm.DB
+	search(query *gorm.DB, terms searchQuery, opts CarListOptions) ([]CarSearchHit, int64, error)
+}
+
+type carRepository struct {
+	db      *gorm.DB
+	dialect carDialect
+}
+
+// NewPostgresCarRepository returns a CarRepository using PostgreSQL array
+// operators and full-text search. It needs the schema from migrations/sql.
+func NewPostgresCarRepository(db *gorm.DB) CarRepository {
+	return &carRepository{db: db, dialect: postgresCars{}}
+}
+
+// NewSQLiteCarRepository returns a CarRepository for SQLite, which is used
+// for local development and tests. Search falls back to substring matching.
+func NewSQLiteCarRepository(db *gorm.DB) CarRepository {
+	return &carRepository{db: db, dialect: sqliteCars{}}
+}
+
+func (r *carRepository) Create(ctx context.Context, car *models.Car) error {
+	car.Version = 1
+	return r.db.WithContext(ctx).Create(car).Error
+}
+
+func (r *carRepository) FindByID(ctx context.Context, id uint) (models.Car, error) {
+	var car models.Car
+	err := r.db.WithContext(ctx).First(&car, id).Error
+	return car, translate(err)
+}
+
+func (r *carRepository) FindByIDUnscoped(ctx context.Context, id uint) (models.Car, error) {
+	var car models.Car
+	err := r.db.WithContext(ctx).Unscoped().First(&car, id).Error
+	return car, translate(err)
+}
+
+func (r *carRepository) FindByOwner(ctx context.Context, userID uint) ([]models.Car, error) {
+	cars := []models.Car{}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&cars).Error
+	return cars, err
+}
+
+func (r *carRepository) Save(ctx context.Context, car *models.Car) error {
+	return r.update(ctx, r.db.WithContext(ctx), car, func(query *gorm.DB) *gorm.DB {
+		return query.Select("*").Omit("id", "created_at").Updates(car)
+	})
+}
+
+func (r *carRepository) UpdateImages(ctx context.Context, car *models.Car) error {
+	return r.update(ctx, r.db.WithContext(ctx), car, func(query *gorm.DB) *gorm.DB {
+		return query.Updates(map[string]interface{}{"images": car.Images, "version": car.Version})
+	})
+}
+
+func (r *carRepository) Delete(ctx context.Context, car *models.Car) error {
+	now := time.Now()
+	err := r.update(ctx, r.db.WithContext(ctx), car, func(query *gorm.DB) *gorm.DB {
+		return query.Updates(map[string]interface{}{"deleted_at": now, "version": car.Version})
+	})
+	if err == nil {
+		car.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	}
+	return err
+}
+
+func (r *carRepository) HardDelete(ctx context.Context, car *models.Car) error {
+	result := r.db.WithContext(ctx).Unscoped().Where("version = ?", car.Version).Delete(car)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *carRepository) Restore(ctx context.Context, car *models.Car) error {
+	err := r.update(ctx, r.db.WithContext(ctx).Unscoped(), car, func(query *gorm.DB) *gorm.DB {
+		return query.Updates(map[string]interface{}{"deleted_at": nil, "version": car.Version})
+	})
+	if err == nil {
+		car.DeletedAt = gorm.DeletedAt{}
+	}
+	return err
+}
+
+func (r *carRepository) SetPublicSlug(ctx context.Context, car *models.Car, slug *string) error {
+	publishedAt := car.PublishedAt
+	if slug == nil {
+		publishedAt = nil
+	} else if publishedAt == nil {
+		now := time.Now()
+		publishedAt = &now
+	}
+
+	err := r.update(ctx, r.db.WithContext(ctx), car, func(query *gorm.DB) *gorm.DB {
+		return query.Updates(map[string]interface{}{
+			"public_slug":  slug,
+			"published_at": publishedAt,
+			"version":      car.Version,
+		})
+	})
+	if err == nil {
+		car.PublicSlug = slug
+		car.PublishedAt = publishedAt
+	}
+	return err
+}
+
+func (r *carRepository) FindByPublicSlug(ctx context.Context, slug string) (models.Car, error) {
+	var car models.Car
+	err := r.db.WithContext(ctx).Where("public_slug = ?", slug).First(&car).Error
+	return car, translate(err)
+}
+
+// update bumps the car's version and runs apply as a compare-and-swap on
+// the version the car was loaded with. apply must write car.Version.
+func (r *carRepository) update(ctx context.Context, db *gorm.DB, car *models.Car, apply func(query *gorm.DB) *gorm.DB) error {
+	loaded := car.Version
+	car.Version++
+
+	result := apply(db.Model(car).Where("version = ?", loaded))
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		car.Version = loaded
+		return result.Error
+	}
+	return nil
+}
+
+func (r *carRepository) ListDeletedByOwner(ctx context.Context, userID uint, opts CarListOptions) ([]models.Car, int64, error) {
+	query := r.db.WithContext(ctx).Unscoped().Model(&models.Car{}).Where("deleted_at IS NOT NULL")
+	if opts.OrganizationID != 0 {
+		query = query.Where("organization_id = ?", opts.OrganizationID)
+	} else {
+		query = query.Where("user_id = ? AND organization_id IS NULL", userID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	cars := []models.Car{}
+	err := query.Order("deleted_at DESC, id DESC").Offset(opts.offset()).Limit(opts.PageSize).Find(&cars).Error
+	return cars, total, err
+}
+
+func (r *carRepository) FindDeletedBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.Car, error) {
+	cars := []models.Car{}
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at, id").Limit(limit).Find(&cars).Error
+	return cars, err
+}
+
+func (r *carRepository) ListForUser(ctx context.Context, userID uint, opts CarListOptions) ([]models.Car, int64, error) {
+	query := r.filter(r.db.WithContext(ctx).Model(&models.Car{}), userID, opts)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	cars := []models.Car{}
+	err := query.Order(opts.orderBy("")).Offset(opts.offset()).Limit(opts.PageSize).Find(&cars).Error
+	return cars, total, err
+}
+
+func (r *carRepository) Search(ctx context.Context, userID uint, query string, opts CarListOptions) ([]CarSearchHit, int64, error) {
+	terms := parseSearchQuery(query)
+	if len(terms) == 0 {
+		return nil, 0, ErrNoSearchTerms
+	}
+	base := r.filter(r.db.WithContext(ctx).Model(&models.Car{}), userID, opts)
+	return r.dialect.search(base, terms, opts)
+}
+
+func (r *carRepository) VehicleConflict(ctx context.Context, car models.Car) (string, error) {
+	checks := []struct{ column, value string }{
+		{"vin", car.VIN},
+		{"registration_plate", car.RegistrationPlate},
+	}
+
+	for _, check := range checks {
+		if check.value == "" {
+			continue
+		}
+		query := r.db.WithContext(ctx).Model(&models.Car{}).
+			Where("id <> ? AND "+check.column+" = ?", car.ID, check.value)
+		if car.OrganizationID != nil {
+			query = query.Where("organization_id = ?", *car.OrganizationID)
+		} else {
+			query = query.Where("user_id = ? AND organization_id IS NULL", car.UserID)
+		}
+
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return check.column, nil
+		}
+	}
+	return "", nil
+}
+
+// filter restricts a car query to the published cars, the organization's
+// fleet or the user's scope and applies the tag and date filters
+func (r *carRepository) filter(query *gorm.DB, userID uint, opts CarListOptions) *gorm.DB {
+	shared := r.db.Model(&models.CarShare{}).Select("car_id").Where("user_id = ? AND accepted_at IS NOT NULL", userID)
+	switch {
+	case opts.Scope == ScopePublished:
+		query = query.Where("cars.public_slug IS NOT NULL")
+	case opts.OrganizationID != 0:
+		query = query.Where("cars.organization_id = ?", opts.OrganizationID)
+	case opts.Scope == ScopeShared:
+		query = query.Where("cars.id IN (?)", shared)
+	case opts.Scope == ScopeAll:
+		query = query.Where("(cars.user_id = ? AND cars.organization_id IS NULL) OR cars.id IN (?)", userID, shared)
+	default:
+		query = query.Where("cars.user_id = ? AND cars.organization_id IS NULL", userID)
+	}
+
+	if len(opts.TagsAny) > 0 {
+		query = r.dialect.tagsAny(query, opts.TagsAny)
+	}
+	if len(opts.TagsAll) > 0 {
+		query = r.dialect.tagsAll(query, opts.TagsAll)
+	}
+	if opts.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *opts.CreatedAfter)
+	}
+	if opts.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *opts.CreatedBefore)
+	}
+	return query
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"gorm.io/gorm"
+)
+
+// ErrMemberExists is returned when the user already belongs to the
+// organization
+var ErrMemberExists = errors.New("user is already a member of the organization")
+
+// OrganizationRepository stores organizations, their members and pending
+// invitations
+type OrganizationRepository interface {
+	// Create stores the organization and makes the creator its owner
+	Create(ctx context.Context, org *models.Organization) (models.OrganizationMember, error)
+	FindByID(ctx context.Context, id uint) (models.Organization, error)
+	Rename(ctx context.Context, org *models.Organization, name string) error
+	// Delete removes the organization with its members and invitations.
+	// It fails while the organization still has cars, including trashed
+	// ones.
+	Delete(ctx context.Context, org *models.Organization) error
+	// CountCars counts the organization's cars, including trashed ones
+	CountCars(ctx context.Context, orgID uint) (int64, error)
+
+	FindMember(ctx context.Context, orgID, userID uint) (models.OrganizationMember, error)
+	// ListMembers returns the members with their users, oldest first
+	ListMembers(ctx context.Context, orgID uint) ([]models.OrganizationMember, error)
+	// ListMemberships returns the user's memberships with their
+	// organizations, oldest first
+	ListMemberships(ctx context.Context, userID uint) ([]models.OrganizationMember, error)
+	CountOwners(ctx context.Context, orgID uint) (int64, error)
+	UpdateMemberRole(ctx context.Context, member *models.OrganizationMember, role string) error
+	// RemoveMember deletes the membership and takes the user's sessions out
+	// of the organization
+	RemoveMember(ctx context.Context, member *models.OrganizationMember) error
+
+	CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error
+	FindInvitation(ctx context.Context, id uint) (models.OrganizationInvitation, error)
+	// ListInvitations returns the organization's pending invitations, newest
+	// first
+	ListInvitations(ctx context.Context, orgID uint) ([]models.OrganizationInvitation, error)
+	// ListInvitationsForEmail returns the unexpired invitations sent to the
+	// email address, with their organizations, newest first
+	ListInvitationsForEmail(ctx context.Context, email string) ([]models.OrganizationInvitation, error)
+	DeleteInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error
+	// AcceptInvitation adds the user to the organization and deletes the
+	// invitation
+	AcceptInvitation(ctx context.Context, invitation *models.OrganizationInvitation, userID uint) (models.OrganizationMember, error)
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *models.Organization) (models.OrganizationMember, error) {
+	member := models.OrganizationMember{UserID: org.CreatedBy, Role: models.OrgOwner}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		member.OrganizationID = org.ID
+		return tx.Omit("Organization", "User").Create(&member).Error
+	})
+	return member, err
+}
+
+func (r *organizationRepository) FindByID(ctx context.Context, id uint) (models.Organization, error) {
+	var org models.Organization
+	err := r.db.WithContext(ctx).First(&org, id).Error
+	return org, translate(err)
+}
+
+func (r *organizationRepository) Rename(ctx context.Context, org *models.Organization, name string) error {
+	if err := r.db.WithContext(ctx).Model(org).Update("name", name).Error; err != nil {
+		return err
+	}
+	org.Name = name
+	return nil
+}
+
+func (r *organizationRepository) Delete(ctx context.Context, org *models.Organization) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Session{}).Where("organization_id = ?", org.ID).
+			Update("organization_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", org.ID).Delete(&models.OrganizationInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", org.ID).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(org).Error
+	})
+}
+
+func (r *organizationRepository) CountCars(ctx context.Context, orgID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Car{}).Where("organization_id = ?", orgID).Count(&count).Error
+	return count, err
+}
+
+func (r *organizationRepository) FindMember(ctx context.Context, orgID, userID uint) (models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := r.db.WithContext(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member).Error
+	return member, translate(err)
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID uint) ([]models.OrganizationMember, error) {
+	members := []models.OrganizationMember{}
+	err := r.db.WithContext(ctx).Preload("User").Where("organization_id = ?", orgID).Order("id").Find(&members).Error
+	return members, err
+}
+
+func (r *organizationRepository) ListMemberships(ctx context.Context, userID uint) ([]models.OrganizationMember, error) {
+	members := []models.OrganizationMember{}
+	err := r.db.WithContext(ctx).Preload("Organization").Where("user_id = ?", userID).Order("id").Find(&members).Error
+	return members, err
+}
+
+func (r *organizationRepository) CountOwners(ctx context.Context, orgID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, models.OrgOwner).Count(&count).Error
+	return count, err
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, member *models.OrganizationMember, role string) error {
+	if err := r.db.WithContext(ctx).Model(member).Update("role", role).Error; err != nil {
+		return err
+	}
+	member.Role = role
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, member *models.OrganizationMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND organization_id = ?", member.UserID, member.OrganizationID).
+			Update("organization_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(member).Error
+	})
+}
+
+func (r *organizationRepository) CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	return r.db.WithContext(ctx).Omit("Organization").Create(invitation).Error
+}
+
+func (r *organizationRepository) FindInvitation(ctx context.Context, id uint) (models.OrganizationInvitation, error) {
+	var invitation models.OrganizationInvitation
+	err := r.db.WithContext(ctx).First(&invitation, id).Error
+	return invitation, translate(err)
+}
+
+func (r *organizationRepository) ListInvitations(ctx context.Context, orgID uint) ([]models.OrganizationInvitation, error) {
+	invitations := []models.OrganizationInvitation{}
+	err := r.db.WithContext(ctx).Where("organization_id = ?", orgID).Order("id DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *organizationRepository) ListInvitationsForEmail(ctx context.Context, email string) ([]models.OrganizationInvitation, error) {
+	invitations := []models.OrganizationInvitation{}
+	err := r.db.WithContext(ctx).Preload("Organization").
+		Where("email = ? AND expires_at > ?", email, time.Now()).
+		Order("id DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *organizationRepository) DeleteInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	return r.db.WithContext(ctx).Delete(invitation).Error
+}
+
+func (r *organizationRepository) AcceptInvitation(ctx context.Context, invitation *models.OrganizationInvitation, userID uint) (models.OrganizationMember, error) {
+	member := models.OrganizationMember{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         userID,
+		Role:           invitation.Role,
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", member.OrganizationID, userID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrMemberExists
+		}
+		if err := tx.Omit("Organization", "User").Create(&member).Error; err != nil {
+			return err
+		}
+		return tx.Delete(invitation).Error
+	})
+	return member, err
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	headlineOptions  = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxWords=35, MinWords=15, MaxFragments=2"
+	searchDictionary = "english"
+)
+
+// postgresCars matches tags with array operators and searches the
+// search_vector column added in migration 0008
+type postgresCars struct{}
+
+func (postgresCars) tagsAny(query *gorm.DB, tags []string) *gorm.DB {
+	return query.Where("tags && ?", pq.StringArray(tags))
+}
+
+func (postgresCars) tagsAll(query *gorm.DB, tags []string) *gorm.DB {
+	return query.Where("tags @> ?", pq.StringArray(tags))
+}
+
+func (postgresCars) search(query *gorm.DB, terms searchQuery, opts CarListOptions) ([]CarSearchHit, int64, error) {
+	query = query.
+		Joins("CROSS JOIN to_tsquery('"+searchDictionary+"', ?) AS query", terms.tsQuery()).
+		Where("search_vector @@ query")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	hits := []CarSearchHit{}
+	if err := query.
+		Select(
+			"cars.*, ts_rank_cd(search_vector, query) AS rank, "+
+				"ts_headline('"+searchDictionary+"', title, query, ?) AS title_highlight, "+
+				"ts_headline('"+searchDictionary+"', coalesce(description, ''), query, ?) AS description_highlight",
+			headlineOptions, headlineOptions,
+		).
+		Order(opts.orderBy("rank")).Offset(opts.offset()).Limit(opts.PageSize).
+		Find(&hits).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for i := range hits {
+		hits[i].TitleHighlight = renderHighlight(hits[i].TitleHighlight)
+		hits[i].DescriptionHighlight = renderHighlight(hits[i].DescriptionHighlight)
+	}
+	return hits, total, nil
+}
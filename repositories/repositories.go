@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// Sort keys accepted by CarListOptions
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortTitle     = "title"
+	SortRelevance = "relevance" // search only
+)
+
+var carSortColumns = map[string]string{
+	SortCreatedAt: "created_at",
+	SortUpdatedAt: "updated_at",
+	SortTitle:     "title",
+}
+
+// Scopes accepted by CarListOptions
+const (
+	ScopeOwned  = "owned"  // the user's own cars (default)
+	ScopeShared = "shared" // cars shared with the user
+	ScopeAll    = "all"    // both
+	// ScopePublished selects every published car, whoever owns it. It is
+	// only used by the public endpoints.
+	ScopePublished = "published"
+)
+
+// CarListOptions holds the paging, sorting and filtering options shared by
+// listing and searching cars
+type CarListOptions struct {
+	// OrganizationID selects the organization's fleet instead of the
+	// user's personal cars; Scope is then ignored
+	OrganizationID uint
+	Scope          string
+	Page           int
+	PageSize       int
+	Sort           string
+	Desc           bool
+	TagsAny        []string
+	TagsAll        []string
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+}
+
+func (o CarListOptions) offset() int {
+	return (o.Page - 1) * o.PageSize
+}
+
+// orderBy returns the ORDER BY clause, with the ID as a tiebreaker so pages
+// are stable. rankColumn is used for relevance sorting.
+func (o CarListOptions) orderBy(rankColumn string) string {
+	direction := "ASC"
+	if o.Desc {
+		direction = "DESC"
+	}
+	column, ok := carSortColumns[o.Sort]
+	if o.Sort == SortRelevance && rankColumn != "" {
+		column, ok = rankColumn, true
+	}
+	if !ok {
+		column = "created_at"
+	}
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction)
+}
+
+// UserListOptions filters the admin user listing
+type UserListOptions struct {
+	Page     int
+	PageSize int
+	Query    string
+	Role     string
+	Disabled *bool
+}
+
+// Repositories bundles the repositories for one database
+type Repositories struct {
+	Cars          CarRepository
+	Users         UserRepository
+	Revisions     RevisionRepository
+	Shares        ShareRepository
+	Organizations OrganizationRepository
+
+	db *gorm.DB
+}
+
+// New returns the repositories matching the database's dialect
+func New(db *gorm.DB) (Repositories, error) {
+	repos := Repositories{
+		Users:         NewUserRepository(db),
+		Revisions:     NewRevisionRepository(db),
+		Shares:        NewShareRepository(db),
+		Organizations: NewOrganizationRepository(db),
+		db:            db,
+	}
+	switch db.Dialector.Name() {
+	case "postgres":
+		repos.Cars = NewPostgresCarRepository(db)
+	case "sqlite":
+		repos.Cars = NewSQLiteCarRepository(db)
+	default:
+		return Repositories{}, fmt.Errorf("unsupported database dialect %q", db.Dialector.Name())
+	}
+	return repos, nil
+}
+
+// Transaction runs fn with repositories bound to a single transaction,
+// which is committed if fn returns nil and rolled back otherwise
+func (r Repositories) Transaction(ctx context.Context, fn func(tx Repositories) error) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		tx, err := New(db)
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// translate maps GORM's not-found error to ErrNotFound
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"gorm.io/gorm"
+)
+
+// RevisionRepository stores the append-only change history of cars
+type RevisionRepository interface {
+	Record(ctx context.Context, revision *models.CarRevision) error
+	FindByID(ctx context.Context, id uint) (models.CarRevision, error)
+	// ListByCar returns a page of the car's revisions, newest first
+	ListByCar(ctx context.Context, carID uint, page, pageSize int) ([]models.CarRevision, int64, error)
+	// ListByCarUpTo returns the car's revisions up to and including the
+	// given one, oldest first
+	ListByCarUpTo(ctx context.Context, carID, revisionID uint) ([]models.CarRevision, error)
+}
+
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+func (r *revisionRepository) Record(ctx context.Context, revision *models.CarRevision) error {
+	return r.db.WithContext(ctx).Create(revision).Error
+}
+
+func (r *revisionRepository) FindByID(ctx context.Context, id uint) (models.CarRevision, error) {
+	var revision models.CarRevision
+	err := r.db.WithContext(ctx).First(&revision, id).Error
+	return revision, translate(err)
+}
+
+func (r *revisionRepository) ListByCar(ctx context.Context, carID uint, page, pageSize int) ([]models.CarRevision, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.CarRevision{}).Where("car_id = ?", carID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	revisions := []models.CarRevision{}
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&revisions).Error
+	return revisions, total, err
+}
+
+func (r *revisionRepository) ListByCarUpTo(ctx context.Context, carID, revisionID uint) ([]models.CarRevision, error) {
+	revisions := []models.CarRevision{}
+	err := r.db.WithContext(ctx).
+		Where("car_id = ? AND id <= ?", carID, revisionID).
+		Order("id").Find(&revisions).Error
+	return revisions, err
+}
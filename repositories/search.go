@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Highlight markers used while building snippets. Control characters cannot
+// appear in the escaped output, so they are swapped for <mark> tags only
+// after the snippet has been HTML-escaped.
+const (
+	highlightStart = "\x01"
+	highlightStop  = "\x02"
+)
+
+// searchTerm is a single word, prefix or phrase of a search query
+type searchTerm struct {
+	Words  []string // more than one word is a phrase
+	Prefix bool
+	Negate bool
+}
+
+// searchQuery is a parsed search: a match needs every term of at least one
+// group, i.e. groups are ORed and the terms within a group ANDed
+type searchQuery [][]searchTerm
+
+// parseSearchQuery parses a user search string. Terms are ANDed together;
+// "quoted phrases" must match in order, a trailing * makes a prefix match,
+// a leading - excludes a term and OR between terms matches either side.
+// Everything except letters and digits is dropped from terms. It returns
+// nil if the input contains no searchable terms.
+func parseSearchQuery(input string) searchQuery {
+	var query searchQuery
+	pendingOr := false
+
+	add := func(term searchTerm) {
+		if len(query) == 0 || pendingOr {
+			query = append(query, nil)
+		}
+		last := len(query) - 1
+		query[last] = append(query[last], term)
+		pendingOr = false
+	}
+
+	for len(input) > 0 {
+		input = strings.TrimLeftFunc(input, unicode.IsSpace)
+		if input == "" {
+			break
+		}
+
+		negate := false
+		if input[0] == '-' {
+			negate = true
+			input = input[1:]
+		}
+
+		if strings.HasPrefix(input, `"`) {
+			end := strings.Index(input[1:], `"`)
+			var phrase string
+			if end < 0 {
+				phrase, input = input[1:], ""
+			} else {
+				phrase, input = input[1:end+1], input[end+2:]
+			}
+			if words := searchWords(phrase); len(words) > 0 {
+				add(searchTerm{Words: words, Negate: negate})
+			}
+			continue
+		}
+
+		end := strings.IndexFunc(input, unicode.IsSpace)
+		var word string
+		if end < 0 {
+			word, input = input, ""
+		} else {
+			word, input = input[:end], input[end:]
+		}
+
+		if strings.EqualFold(word, "or") && !negate {
+			pendingOr = len(query) > 0
+			continue
+		}
+
+		words := searchWords(word)
+		if len(words) == 0 {
+			continue
+		}
+		add(searchTerm{Words: words, Prefix: len(words) == 1 && strings.HasSuffix(word, "*"), Negate: negate})
+	}
+
+	return query
+}
+
+// tsQuery renders the query in to_tsquery syntax. Terms only contain
+// letters and digits, so the result is always safe to pass to to_tsquery.
+func (q searchQuery) tsQuery() string {
+	groups := make([]string, 0, len(q))
+	for _, group := range q {
+		terms := make([]string, 0, len(group))
+		for _, t := range group {
+			term := strings.Join(t.Words, " <-> ")
+			if len(t.Words) > 1 {
+				term = "(" + term + ")"
+			} else if t.Prefix {
+				term += ":*"
+			}
+			if t.Negate {
+				term = "!" + term
+			}
+			terms = append(terms, term)
+		}
+		groups = append(groups, strings.Join(terms, " & "))
+	}
+	return strings.Join(groups, " | ")
+}
+
+// positiveTerms returns the terms that are not negated
+func (q searchQuery) positiveTerms() []searchTerm {
+	var terms []searchTerm
+	for _, group := range q {
+		for _, t := range group {
+			if !t.Negate {
+				terms = append(terms, t)
+			}
+		}
+	}
+	return terms
+}
+
+// searchWords splits s into runs of letters and digits
+func searchWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// renderHighlight HTML-escapes a snippet and turns the highlight markers
+// into <mark> tags
+func renderHighlight(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, highlightStart, "<mark>")
+	return strings.ReplaceAll(s, highlightStop, "</mark>")
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import "testing"
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // in to_tsquery syntax
+	}{
+		{"word", "toyota", "toyota"},
+		{"words are ANDed", "toyota corolla", "toyota & corolla"},
+		{"phrase", `"land cruiser"`, "(land <-> cruiser)"},
+		{"unterminated phrase", `"land cruiser`, "(land <-> cruiser)"},
+		{"prefix", "toy*", "toy:*"},
+		{"negated word", "toyota -diesel", "toyota & !diesel"},
+		{"negated phrase", `-"land cruiser"`, "!(land <-> cruiser)"},
+		{"or", "toyota OR honda", "toyota | honda"},
+		{"or binds looser than and", "red toyota or blue honda", "red & toyota | blue & honda"},
+		{"leading or", "or toyota", "toyota"},
+		{"trailing or", "toyota or", "toyota"},
+		{"negated or is a word", "-or", "!or"},
+		{"punctuation splits words", "f-150", "(f <-> 150)"},
+		{"punctuation is dropped", "c++ (sedan)", "c & sedan"},
+		{"unicode letters", "Straße", "Straße"},
+		{"nothing searchable", `"" *** - !`, ""},
+		{"empty", "   ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := parseSearchQuery(tt.input)
+			if got := query.tsQuery(); got != tt.want {
+				t.Errorf("parseSearchQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if tt.want == "" && query != nil {
+				t.Errorf("parseSearchQuery(%q) = %v, want nil", tt.input, query)
+			}
+		})
+	}
+}
+
+func TestPositiveTerms(t *testing.T) {
+	terms := parseSearchQuery(`toyota -diesel or "land cruiser"`).positiveTerms()
+	if len(terms) != 2 || terms[0].Words[0] != "toyota" || len(terms[1].Words) != 2 {
+		t.Errorf("positiveTerms = %v, want toyota and the phrase", terms)
+	}
+}
+
+func TestRenderHighlight(t *testing.T) {
+	got := renderHighlight("<b>" + highlightStart + "Corolla" + highlightStop + " & co")
+	if want := "&lt;b&gt;<mark>Corolla</mark> &amp; co"; got != want {
+		t.Errorf("renderHighlight = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"gorm.io/gorm"
+)
+
+// ErrShareExists is returned by Create when the car is already shared with
+// the email address
+var ErrShareExists = errors.New("car is already shared with this email")
+
+// ShareRepository stores car shares. Lookups preload nothing unless noted.
+type ShareRepository interface {
+	Create(ctx context.Context, share *models.CarShare) error
+	FindByID(ctx context.Context, id uint) (models.CarShare, error)
+	// FindByCarAndUser returns the share of the car the user has accepted
+	FindByCarAndUser(ctx context.Context, carID, userID uint) (models.CarShare, error)
+	// ListByCar returns the car's shares with their users, oldest first
+	ListByCar(ctx context.Context, carID uint) ([]models.CarShare, error)
+	// ListPendingForEmail returns the shares sent to the (normalized) email
+	// that have not been accepted yet, with their cars, newest first
+	ListPendingForEmail(ctx context.Context, email string) ([]models.CarShare, error)
+	// FindAcceptedForCars returns the user's accepted shares of the given
+	// cars keyed by car ID
+	FindAcceptedForCars(ctx context.Context, userID uint, carIDs []uint) (map[uint]models.CarShare, error)
+	// Accept links the share to the user accepting it
+	Accept(ctx context.Context, share *models.CarShare, userID uint) error
+	UpdateRole(ctx context.Context, share *models.CarShare, role string) error
+	Delete(ctx context.Context, share *models.CarShare) error
+}
+
+type shareRepository struct {
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) ShareRepository {
+	return &shareRepository{db: db}
+}
+
+func (r *shareRepository) Create(ctx context.Context, share *models.CarShare) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.CarShare{}).
+		Where("car_id = ? AND email = ?", share.CarID, share.Email).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrShareExists
+	}
+	return r.db.WithContext(ctx).Omit("User", "Car").Create(share).Error
+}
+
+func (r *shareRepository) FindByID(ctx context.Context, id uint) (models.CarShare, error) {
+	var share models.CarShare
+	err := r.db.WithContext(ctx).First(&share, id).Error
+	return share, translate(err)
+}
+
+func (r *shareRepository) FindByCarAndUser(ctx context.Context, carID, userID uint) (models.CarShare, error) {
+	var share models.CarShare
+	err := r.db.WithContext(ctx).Where("car_id = ? AND user_id = ?", carID, userID).First(&share).Error
+	return share, translate(err)
+}
+
+func (r *shareRepository) ListByCar(ctx context.Context, carID uint) ([]models.CarShare, error) {
+	shares := []models.CarShare{}
+	err := r.db.WithContext(ctx).Preload("User").Where("car_id = ?", carID).Order("id").Find(&shares).Error
+	return shares, err
+}
+
+func (r *shareRepository) ListPendingForEmail(ctx context.Context, email string) ([]models.CarShare, error) {
+	shares := []models.CarShare{}
+	err := r.db.WithContext(ctx).Preload("Car").
+		Joins("JOIN cars ON cars.id = car_shares.car_id AND cars.deleted_at IS NULL").
+		Where("car_shares.email = ? AND car_shares.accepted_at IS NULL", email).
+		Order("car_shares.id DESC").Find(&shares).Error
+	return shares, err
+}
+
+func (r *shareRepository) FindAcceptedForCars(ctx context.Context, userID uint, carIDs []uint) (map[uint]models.CarShare, error) {
+	result := map[uint]models.CarShare{}
+	if len(carIDs) == 0 {
+		return result, nil
+	}
+
+	shares := []models.CarShare{}
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND car_id IN ? AND accepted_at IS NOT NULL", userID, carIDs).
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	for _, share := range shares {
+		result[share.CarID] = share
+	}
+	return result, nil
+}
+
+func (r *shareRepository) Accept(ctx context.Context, share *models.CarShare, userID uint) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(share).Updates(map[string]interface{}{
+		"user_id":     userID,
+		"accepted_at": now,
+	}).Error; err != nil {
+		return err
+	}
+	share.UserID = &userID
+	share.AcceptedAt = &now
+	return nil
+}
+
+func (r *shareRepository) UpdateRole(ctx context.Context, share *models.CarShare, role string) error {
+	if err := r.db.WithContext(ctx).Model(share).Update("role", role).Error; err != nil {
+		return err
+	}
+	share.Role = role
+	return nil
+}
+
+func (r *shareRepository) Delete(ctx context.Context, share *models.CarShare) error {
+	return r.db.WithContext(ctx).Delete(share).Error
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// sqliteDocument is the text searched by sqliteCars. Tags are stored in
+// PostgreSQL array syntax, which still contains every tag verbatim.
+const sqliteDocument = `lower(title || ' ' || coalesce(description, '') || ' ' || coalesce(make, '') || ' ' || ` +
+	`coalesce(model, '') || ' ' || coalesce("trim", '') || ' ' || coalesce(colour, '') || ' ' || coalesce(tags, ''))`
+
+// sqliteCars stands in for the PostgreSQL features with plain SQL. Arrays
+// are stored as text in PostgreSQL's {"a","b"} format, and search uses
+// case-insensitive substring matching without stemming.
+type sqliteCars struct{}
+
+func (sqliteCars) tagsAny(query *gorm.DB, tags []string) *gorm.DB {
+	conditions := make([]string, 0, len(tags))
+	var args []interface{}
+	for _, tag := range tags {
+		condition, tagArgs := sqliteHasTag(tag)
+		conditions = append(conditions, condition)
+		args = append(args, tagArgs...)
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+func (sqliteCars) tagsAll(query *gorm.DB, tags []string) *gorm.DB {
+	for _, tag := range tags {
+		condition, args := sqliteHasTag(tag)
+		query = query.Where(condition, args...)
+	}
+	return query
+}
+
+// sqliteHasTag matches a tag inside the array text. The lib/pq encoder
+// always quotes elements, so a tag is an exact quoted element between
+// braces or commas.
+func sqliteHasTag(tag string) (string, []interface{}) {
+	element := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(tag) + `"`
+	return "(instr(tags, ?) > 0 OR instr(tags, ?) > 0 OR instr(tags, ?) > 0 OR instr(tags, ?) > 0)",
+		[]interface{}{"{" + element + "}", "{" + element + ",", "," + element + ",", "," + element + "}"}
+}
+
+func (sqliteCars) search(query *gorm.DB, terms searchQuery, opts CarListOptions) ([]CarSearchHit, int64, error) {
+	groups := make([]string, 0, len(terms))
+	var args []interface{}
+	for _, group := range terms {
+		conditions := make([]string, 0, len(group))
+		for _, t := range group {
+			condition := "instr(" + sqliteDocument + ", ?) > 0"
+			if t.Negate {
+				condition = "NOT " + condition
+			}
+			conditions = append(conditions, condition)
+			args = append(args, sqliteNeedle(t))
+		}
+		groups = append(groups, "("+strings.Join(conditions, " AND ")+")")
+	}
+	query = query.Where("("+strings.Join(groups, " OR ")+")", args...)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Matches in the title count double, mirroring the weights of the
+	// PostgreSQL search vector
+	positive := terms.positiveTerms()
+	rank := []string{"0"}
+	var rankArgs []interface{}
+	for _, t := range positive {
+		rank = append(rank, "(instr(lower(title), ?) > 0) * 2 + (instr("+sqliteDocument+", ?) > 0)")
+		rankArgs = append(rankArgs, sqliteNeedle(t), sqliteNeedle(t))
+	}
+
+	hits := []CarSearchHit{}
+	if err := query.
+		Select("cars.*, "+strings.Join(rank, " + ")+" AS rank", rankArgs...).
+		Order(opts.orderBy("rank")).Offset(opts.offset()).Limit(opts.PageSize).
+		Find(&hits).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for i := range hits {
+		hits[i].TitleHighlight = renderHighlight(markTerms(hits[i].Title, positive))
+		hits[i].DescriptionHighlight = renderHighlight(markTerms(hits[i].Description, positive))
+	}
+	return hits, total, nil
+}
+
+func sqliteNeedle(t searchTerm) string {
+	return strings.ToLower(strings.Join(t.Words, " "))
+}
+
+// markTerms wraps case-insensitive occurrences of the terms in highlight
+// markers. Text whose lower-case form changes length is left unmarked.
+func markTerms(text string, terms []searchTerm) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) || len(terms) == 0 {
+		return text
+	}
+
+	marked := make([]bool, len(text))
+	for _, t := range terms {
+		needle := sqliteNeedle(t)
+		for start := 0; ; {
+			i := strings.Index(lower[start:], needle)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(needle); j++ {
+				marked[j] = true
+			}
+			start += i + len(needle)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(highlightStart)
+		}
+		b.WriteByte(text[i])
+		if marked[i] && (i == len(text)-1 || !marked[i+1]) {
+			b.WriteString(highlightStop)
+		}
+	}
+	return b.String()
+}
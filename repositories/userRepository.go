@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"gorm.io/gorm"
+)
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id uint) (models.User, error)
+	// FindByEmail matches the email however it is cased
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+	List(ctx context.Context, opts UserListOptions) ([]models.User, int64, error)
+	// Update writes the given columns of the user
+	Update(ctx context.Context, user *models.User, fields map[string]interface{}) error
+	// Disable marks the account disabled and revokes all of its sessions
+	Disable(ctx context.Context, user *models.User) error
+}
+
+// userRepository only uses portable SQL, so it serves both PostgreSQL and
+// SQLite
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uint) (models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, id).Error
+	return user, translate(err)
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("email = ?", models.NormalizeEmail(email)).First(&user).Error
+	return user, translate(err)
+}
+
+func (r *userRepository) List(ctx context.Context, opts UserListOptions) ([]models.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{})
+	if q := strings.TrimSpace(opts.Query); q != "" {
+		like := "%" + escapeLike(strings.ToLower(q)) + "%"
+		query = query.Where(`LOWER(username) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, like, like)
+	}
+	if opts.Role != "" {
+		query = query.Where("role = ?", opts.Role)
+	}
+	if opts.Disabled != nil {
+		if *opts.Disabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	users := []models.User{}
+	err := query.Order("id").Offset((opts.Page - 1) * opts.PageSize).Limit(opts.PageSize).Find(&users).Error
+	return users, total, err
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(user).Updates(fields).Error
+}
+
+func (r *userRepository) Disable(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("disabled_at", now).Error; err != nil {
+			return err
+		}
+		return models.RevokeUserSessions(tx, user.ID)
+	})
+	if err == nil {
+		user.DisabledAt = &now
+	}
+	return err
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/controllers"
+	"github.com/akashkumar7902/car-management-backend/middlewares"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func AdminRoutes(r *gin.Engine, db *gorm.DB, repos repositories.Repositories, cfg config.Config, store storage.ImageStore, limiter ratelimit.Store) {
+	adminController := controllers.AdminController{
+		Repos: repos,
+		Cfg:   cfg,
+		Store: store,
+	}
+
+	staff := middlewares.RequireRole(models.RoleSupport, models.RoleAdmin)
+	adminOnly := middlewares.RequireRole(models.RoleAdmin)
+
+	rateLimit := middlewares.RateLimit(limiter, "api", cfg.APIRateLimit, middlewares.ByUser)
+
+	admin := r.Group("/api/admin").Use(middlewares.AuthMiddleware(db, cfg), rateLimit, staff, middlewares.Idempotency(db, cfg))
+	{
+		admin.GET("/users", adminController.ListUsers)
+		admin.GET("/users/:id", adminController.GetUser)
+		admin.GET("/users/:id/cars", adminController.ListUserCars)
+		admin.POST("/users/:id/disable", adminController.DisableUser)
+		admin.POST("/users/:id/enable", adminController.EnableUser)
+		admin.PUT("/users/:id/role", adminOnly, adminController.UpdateUserRole)
+		admin.DELETE("/cars/:id", adminOnly, adminController.DeleteCar)
+	}
+}
@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// adminUser is the part of an AdminUser the tests look at
+type adminUser struct {
+	ID         uint        `json:"id"`
+	Email      string      `json:"email"`
+	Role       string      `json:"role"`
+	DisabledAt interface{} `json:"disabled_at"`
+}
+
+func adminUserPath(id uint, rest ...string) string {
+	return fmt.Sprintf("/api/admin/users/%d", id) + strings.Join(rest, "")
+}
+
+func TestAdminRoles(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	support := s.newUserWithRole("sam", true, models.RoleSupport)
+	admin := s.newUserWithRole("ada", true, models.RoleAdmin)
+	car := s.createCar(alice, map[string]string{"title": "Reported car"})
+
+	// Only staff get in
+	s.expect(request{method: "GET", path: "/api/admin/users"}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "GET", path: "/api/admin/users", token: alice.token}, http.StatusForbidden, nil)
+
+	var users struct {
+		Data       []adminUser `json:"data"`
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+	s.expect(request{method: "GET", path: "/api/admin/users", token: support.token}, http.StatusOK, &users)
+	if users.Pagination.Total != 3 {
+		t.Errorf("users = %+v, want 3", users)
+	}
+	s.expect(request{method: "GET", path: "/api/admin/users?q=alice", token: support.token}, http.StatusOK, &users)
+	if len(users.Data) != 1 || users.Data[0].ID != alice.ID {
+		t.Errorf("users matching alice = %+v", users.Data)
+	}
+	s.expect(request{method: "GET", path: "/api/admin/users?role=staff", token: support.token}, http.StatusBadRequest, nil)
+
+	var cars []testCar
+	s.expect(request{method: "GET", path: adminUserPath(alice.ID, "/cars"), token: support.token}, http.StatusOK, &cars)
+	if len(cars) != 1 || cars[0].ID != car.ID {
+		t.Errorf("alice's cars = %+v", cars)
+	}
+	s.expect(request{method: "GET", path: adminUserPath(999), token: support.token}, http.StatusNotFound, nil)
+
+	// Support can disable users, which ends their sessions at once
+	var disabled adminUser
+	s.expect(request{method: "POST", path: adminUserPath(alice.ID, "/disable"), token: support.token}, http.StatusOK, &disabled)
+	if disabled.DisabledAt == nil {
+		t.Errorf("disabled user = %+v", disabled)
+	}
+	s.expect(request{method: "GET", path: "/api/cars", token: alice.token}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": alice.Email, "password": testPassword}}, http.StatusForbidden, nil)
+	s.expect(request{method: "POST", path: adminUserPath(alice.ID, "/enable"), token: support.token}, http.StatusOK, nil)
+	alice.token = s.login(alice.Email)
+
+	s.expect(request{method: "POST", path: adminUserPath(admin.ID, "/disable"), token: support.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "POST", path: adminUserPath(support.ID, "/disable"), token: support.token}, http.StatusBadRequest, nil)
+
+	// Roles and deleting cars are for admins only
+	s.expect(request{method: "PUT", path: adminUserPath(alice.ID, "/role"), token: support.token, body: gin.H{"role": "admin"}}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("/api/admin/cars/%d", car.ID), token: support.token}, http.StatusForbidden, nil)
+
+	var promoted adminUser
+	s.expect(request{method: "PUT", path: adminUserPath(alice.ID, "/role"), token: admin.token, body: gin.H{"role": "support"}}, http.StatusOK, &promoted)
+	if promoted.Role != models.RoleSupport {
+		t.Errorf("promoted user = %+v", promoted)
+	}
+	s.expect(request{method: "GET", path: "/api/admin/users", token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "PUT", path: adminUserPath(alice.ID, "/role"), token: admin.token, body: gin.H{"role": "owner"}}, http.StatusBadRequest, nil)
+	s.expect(request{method: "PUT", path: adminUserPath(admin.ID, "/role"), token: admin.token, body: gin.H{"role": "user"}}, http.StatusBadRequest, nil)
+
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("/api/admin/cars/%d", car.ID), token: admin.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: carPath(car.ID), token: alice.token}, http.StatusNotFound, nil)
+}
@@ -3,19 +3,47 @@ package routes
 import (
 	"github.com/akashkumar7902/car-management-backend/config"
 	"github.com/akashkumar7902/car-management-backend/controllers"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/middlewares"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func AuthRoutes(r *gin.Engine, db *gorm.DB, cfg config.Config) {
+func AuthRoutes(r *gin.Engine, db *gorm.DB, repos repositories.Repositories, cfg config.Config, m mailer.Mailer, limiter ratelimit.Store) {
 	authController := controllers.AuthController{
-		DB:  db,
-		Cfg: cfg,
+		DB:     db,
+		Users:  repos.Users,
+		Cfg:    cfg,
+		Mailer: m,
 	}
+	if limiter != nil {
+		authController.Lockout = ratelimit.NewLockout(limiter, cfg)
+	}
+
+	authMiddleware := middlewares.AuthMiddleware(db, cfg)
+
+	// Logins are limited per address and per account, on top of the
+	// lockout after repeated failures
+	loginLimit := middlewares.RateLimit(limiter, "login", cfg.LoginRateLimit, middlewares.ByIP)
+	accountLimit := middlewares.RateLimit(limiter, "login-account", cfg.LoginAccountRateLimit, middlewares.ByEmail)
+	signupLimit := middlewares.RateLimit(limiter, "signup", cfg.SignupRateLimit, middlewares.ByIP)
+	authLimit := middlewares.RateLimit(limiter, "auth", cfg.AuthRateLimit, middlewares.ByIP)
 
 	auth := r.Group("/api/users")
 	{
-		auth.POST("/signup", authController.RegisterUser)
-		auth.POST("/login", authController.LoginUser)
+		auth.POST("/signup", signupLimit, authController.RegisterUser)
+		auth.POST("/login", loginLimit, accountLimit, authController.LoginUser)
+		auth.POST("/refresh", authLimit, authController.RefreshToken)
+		auth.POST("/logout", authLimit, authMiddleware, authController.LogoutUser)
+		auth.POST("/password/forgot", authLimit, authController.ForgotPassword)
+		auth.POST("/password/reset", authLimit, authController.ResetPassword)
+		auth.POST("/verify", authLimit, authController.VerifyEmail)
+		auth.POST("/verify/resend", authLimit, authController.ResendVerification)
+		auth.POST("/login/2fa", loginLimit, authController.LoginTwoFactor)
+		auth.POST("/2fa/setup", authLimit, authMiddleware, authController.SetupTwoFactor)
+		auth.POST("/2fa/confirm", authLimit, authMiddleware, authController.ConfirmTwoFactor)
+		auth.POST("/2fa/disable", authLimit, authMiddleware, authController.DisableTwoFactor)
 	}
 }
@@ -0,0 +1,289 @@
+package routes
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSignupVerifyAndLogin(t *testing.T) {
+	s := newTestServer(t)
+
+	var signup struct {
+		ID           uint   `json:"id"`
+		Email        string `json:"email"`
+		Verified     bool   `json:"verified"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/signup", body: gin.H{
+		"username": "alice", "email": "Alice@Example.com", "password": testPassword,
+	}}, http.StatusCreated, &signup)
+	if signup.Email != "alice@example.com" || signup.Verified || signup.Token == "" || signup.RefreshToken == "" {
+		t.Fatalf("signup = %+v, want an unverified account with a normalized email and tokens", signup)
+	}
+
+	// The same address in another case is the same account
+	w := s.expect(request{method: "POST", path: "/api/users/signup", body: gin.H{
+		"username": "alice2", "email": "ALICE@example.com", "password": testPassword,
+	}}, http.StatusBadRequest, nil)
+	if code := errorCode(t, w); code != "bad_request" {
+		t.Errorf("duplicate signup code = %q", code)
+	}
+
+	token := tokenIn(t, s.mailTo("alice@example.com"))
+	s.expect(request{method: "POST", path: "/api/users/verify", body: gin.H{"token": token}}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: "/api/users/verify", body: gin.H{"token": token}}, http.StatusBadRequest, nil)
+
+	var login struct {
+		ID       uint   `json:"id"`
+		Verified bool   `json:"verified"`
+		Token    string `json:"token"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{
+		"email": "ALICE@EXAMPLE.COM", "password": testPassword,
+	}}, http.StatusOK, &login)
+	if login.ID != signup.ID || !login.Verified || login.Token == "" {
+		t.Errorf("login = %+v, want the verified account", login)
+	}
+
+	w = s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{
+		"email": "alice@example.com", "password": "wrong",
+	}}, http.StatusUnauthorized, nil)
+	if code := errorCode(t, w); code != "invalid_credentials" {
+		t.Errorf("wrong password code = %q", code)
+	}
+}
+
+func TestRefreshAndLogout(t *testing.T) {
+	s := newTestServer(t)
+	s.newUser("bob", true)
+
+	var login struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": "bob@example.com", "password": testPassword}}, http.StatusOK, &login)
+
+	var refreshed struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/refresh", body: gin.H{"refresh_token": login.RefreshToken}}, http.StatusOK, &refreshed)
+	if refreshed.RefreshToken == login.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	s.expect(request{method: "GET", path: "/api/cars", token: refreshed.Token}, http.StatusOK, nil)
+
+	// Presenting the rotated-out token again revokes the session
+	s.expect(request{method: "POST", path: "/api/users/refresh", body: gin.H{"refresh_token": login.RefreshToken}}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: "/api/users/refresh", body: gin.H{"refresh_token": refreshed.RefreshToken}}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "GET", path: "/api/cars", token: refreshed.Token}, http.StatusUnauthorized, nil)
+
+	token := s.login("bob@example.com")
+	s.expect(request{method: "POST", path: "/api/users/logout", token: token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: "/api/cars", token: token}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "GET", path: "/api/cars"}, http.StatusUnauthorized, nil)
+}
+
+func TestSessionMaxAge(t *testing.T) {
+	s := newTestServer(t)
+	s.newUser("bob", true)
+
+	var login struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": "bob@example.com", "password": testPassword}}, http.StatusOK, &login)
+
+	var session models.Session
+	if err := s.db.Last(&session).Error; err != nil {
+		t.Fatal(err)
+	}
+	if want := session.CreatedAt.Add(s.cfg.SessionMaxAge); session.MaxExpiresAt.Sub(want).Abs() > time.Minute {
+		t.Errorf("max expiry = %v, want %v", session.MaxExpiresAt, want)
+	}
+
+	// Refreshing close to the end of the session does not extend it
+	maxExpiresAt := time.Now().Add(time.Hour)
+	s.db.Model(&session).Update("max_expires_at", maxExpiresAt)
+	var refreshed struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/refresh", body: gin.H{"refresh_token": login.RefreshToken}}, http.StatusOK, &refreshed)
+	s.db.First(&session, session.ID)
+	if session.ExpiresAt.After(maxExpiresAt) {
+		t.Errorf("refreshed session expires at %v, after its maximum %v", session.ExpiresAt, maxExpiresAt)
+	}
+
+	// Past the maximum the refresh token is refused
+	s.db.Model(&session).Update("max_expires_at", time.Now().Add(-time.Minute))
+	s.expect(request{method: "POST", path: "/api/users/refresh", body: gin.H{"refresh_token": refreshed.RefreshToken}}, http.StatusUnauthorized, nil)
+}
+
+func TestLoginLockout(t *testing.T) {
+	s := newTestServer(t)
+	s.newUser("carol", true)
+
+	attempt := func(email, password, ip string) int {
+		return s.do(request{method: "POST", path: "/api/users/login", ip: ip,
+			body: gin.H{"email": email, "password": password}}).Code
+	}
+
+	// LoginLockoutThreshold is 3
+	for i := 0; i < 3; i++ {
+		if code := attempt("carol@example.com", "wrong", "192.0.2.1"); code != http.StatusUnauthorized {
+			t.Fatalf("failure %d: status %d, want 401", i+1, code)
+		}
+	}
+	if code := attempt("carol@example.com", testPassword, "192.0.2.1"); code != http.StatusTooManyRequests {
+		t.Errorf("locked account: status %d, want 429", code)
+	}
+
+	// The lock holds for the account, whichever address asks
+	if code := attempt("Carol@Example.com", testPassword, "192.0.2.2"); code != http.StatusTooManyRequests {
+		t.Errorf("other address: status %d, want 429", code)
+	}
+
+	// Unknown emails lock the same way, so locking does not reveal accounts
+	for i := 0; i < 3; i++ {
+		attempt("nobody@example.com", "wrong", "192.0.2.1")
+	}
+	if code := attempt("nobody@example.com", "wrong", "192.0.2.1"); code != http.StatusTooManyRequests {
+		t.Errorf("unknown email: status %d, want 429", code)
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	s := newTestServer(t)
+	dave := s.newUser("dave", true)
+
+	// Unknown addresses get the same answer and no email
+	s.expect(request{method: "POST", path: "/api/users/password/forgot", body: gin.H{"email": "nobody@example.com"}}, http.StatusOK, nil)
+	if s.mailCount("nobody@example.com") != 0 {
+		t.Error("reset link sent to an unknown address")
+	}
+
+	s.expect(request{method: "POST", path: "/api/users/password/forgot", body: gin.H{"email": "dave@example.com"}}, http.StatusOK, nil)
+	token := tokenIn(t, s.mailTo("dave@example.com"))
+	s.expect(request{method: "POST", path: "/api/users/password/reset", body: gin.H{"token": token, "password": "short"}}, http.StatusBadRequest, nil)
+	s.expect(request{method: "POST", path: "/api/users/password/reset", body: gin.H{"token": token, "password": "new-password"}}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: "/api/users/password/reset", body: gin.H{"token": token, "password": "other-password"}}, http.StatusBadRequest, nil)
+
+	// Resetting the password ends every session
+	s.expect(request{method: "GET", path: "/api/cars", token: dave.token}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": "dave@example.com", "password": testPassword}}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": "dave@example.com", "password": "new-password"}}, http.StatusOK, nil)
+}
+
+func TestResendVerification(t *testing.T) {
+	s := newTestServer(t)
+	erin := s.newUser("erin", false)
+	s.newUser("frank", true)
+
+	s.expect(request{method: "POST", path: "/api/users/verify/resend", body: gin.H{"email": "frank@example.com"}}, http.StatusOK, nil)
+	if s.mailCount("frank@example.com") != 0 {
+		t.Error("verification link sent to a verified account")
+	}
+
+	s.expect(request{method: "POST", path: "/api/users/verify/resend", body: gin.H{"email": "erin@example.com"}}, http.StatusOK, nil)
+	first := tokenIn(t, s.mailTo("erin@example.com"))
+	s.expect(request{method: "POST", path: "/api/users/verify/resend", body: gin.H{"email": "erin@example.com"}}, http.StatusOK, nil)
+	second := tokenIn(t, s.mailTo("erin@example.com"))
+
+	// Only the latest link works
+	s.expect(request{method: "POST", path: "/api/users/verify", body: gin.H{"token": first}}, http.StatusBadRequest, nil)
+	s.expect(request{method: "POST", path: "/api/users/verify", body: gin.H{"token": second}}, http.StatusOK, nil)
+
+	var login struct {
+		ID       uint `json:"id"`
+		Verified bool `json:"verified"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": "erin@example.com", "password": testPassword}}, http.StatusOK, &login)
+	if login.ID != erin.ID || !login.Verified {
+		t.Errorf("login = %+v, want the verified account", login)
+	}
+}
+
+func TestTwoFactor(t *testing.T) {
+	s := newTestServer(t)
+	grace := s.newUser("grace", true)
+
+	var setup struct {
+		Secret     string `json:"secret"`
+		OTPAuthURL string `json:"otpauth_url"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/2fa/setup"}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: "/api/users/2fa/setup", token: grace.token}, http.StatusOK, &setup)
+	if setup.Secret == "" || !strings.HasPrefix(setup.OTPAuthURL, "otpauth://totp/") {
+		t.Fatalf("setup = %+v", setup)
+	}
+
+	now := time.Now()
+	s.expect(request{method: "POST", path: "/api/users/2fa/confirm", token: grace.token, body: gin.H{"code": "000000"}}, http.StatusBadRequest, nil)
+	var confirm struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/2fa/confirm", token: grace.token, body: gin.H{"code": totpAt(t, setup.Secret, now)}}, http.StatusOK, &confirm)
+	if len(confirm.RecoveryCodes) != 10 {
+		t.Fatalf("recovery codes = %v", confirm.RecoveryCodes)
+	}
+	s.expect(request{method: "POST", path: "/api/users/2fa/setup", token: grace.token}, http.StatusConflict, nil)
+
+	// Logging in now takes a second step
+	challenge := func() string {
+		var login struct {
+			TwoFactorRequired bool   `json:"two_factor_required"`
+			ChallengeToken    string `json:"challenge_token"`
+			Token             string `json:"token"`
+		}
+		s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": "grace@example.com", "password": testPassword}}, http.StatusOK, &login)
+		if !login.TwoFactorRequired || login.ChallengeToken == "" || login.Token != "" {
+			t.Fatalf("login = %+v, want a challenge", login)
+		}
+		return login.ChallengeToken
+	}
+	var session struct {
+		Token string `json:"token"`
+	}
+	token := challenge()
+	s.expect(request{method: "POST", path: "/api/users/login/2fa", body: gin.H{"challenge_token": token, "code": totpAt(t, setup.Secret, now)}}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: "/api/users/login/2fa", body: gin.H{"challenge_token": token, "code": totpAt(t, setup.Secret, now.Add(30*time.Second))}}, http.StatusOK, &session)
+	s.expect(request{method: "GET", path: "/api/cars", token: session.Token}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: "/api/users/login/2fa", body: gin.H{"challenge_token": session.Token, "code": totpAt(t, setup.Secret, now)}}, http.StatusUnauthorized, nil)
+
+	// Recovery codes work once
+	recovery := confirm.RecoveryCodes[0]
+	s.expect(request{method: "POST", path: "/api/users/login/2fa", body: gin.H{"challenge_token": challenge(), "recovery_code": recovery}}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: "/api/users/login/2fa", body: gin.H{"challenge_token": challenge(), "recovery_code": recovery}}, http.StatusUnauthorized, nil)
+
+	// Disabling takes the password and a second factor
+	s.expect(request{method: "POST", path: "/api/users/2fa/disable", token: grace.token, body: gin.H{"password": "wrong", "recovery_code": confirm.RecoveryCodes[1]}}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: "/api/users/2fa/disable", token: grace.token, body: gin.H{"password": testPassword, "recovery_code": confirm.RecoveryCodes[1]}}, http.StatusOK, nil)
+	s.login("grace@example.com")
+}
+
+// totpAt computes the RFC 6238 code for the secret at t
+func totpAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
@@ -3,29 +3,56 @@ package routes
 import (
 	"github.com/akashkumar7902/car-management-backend/config"
 	"github.com/akashkumar7902/car-management-backend/controllers"
+	"github.com/akashkumar7902/car-management-backend/mailer"
 	"github.com/akashkumar7902/car-management-backend/middlewares"
-	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func CarRoutes(r *gin.Engine, db *gorm.DB, cfg config.Config, cld *cloudinary.Cloudinary) {
+func CarRoutes(r *gin.Engine, db *gorm.DB, repos repositories.Repositories, cfg config.Config, store storage.ImageStore, m mailer.Mailer, limiter ratelimit.Store) {
 	carController := controllers.CarController{
-		DB:         db,
-		Cfg:        cfg,
-		Cloudinary: cld,
+		Repos:  repos,
+		Cfg:    cfg,
+		Store:  store,
+		Mailer: m,
 	}
 
 	// Apply authentication middleware
 	authMiddleware := middlewares.AuthMiddleware(db, cfg)
+	verified := middlewares.RequireVerifiedEmail(cfg)
+	idempotency := middlewares.Idempotency(db, cfg)
+	organization := middlewares.ActiveOrganization(repos.Organizations)
+	rateLimit := middlewares.RateLimit(limiter, "api", cfg.APIRateLimit, middlewares.ByUser)
 
-	cars := r.Group("/api/cars").Use(authMiddleware)
+	cars := r.Group("/api/cars").Use(authMiddleware, rateLimit, organization, idempotency)
 	{
-		cars.POST("", carController.CreateCar)
+		cars.POST("", verified, carController.CreateCar)
 		cars.GET("", carController.ListCars)
 		cars.GET("/search", carController.SearchCars)
+		cars.GET("/trash", carController.ListTrash)
+		cars.GET("/shared", carController.ListSharedCars)
+		cars.GET("/invitations", carController.ListCarInvitations)
+		cars.POST("/invitations/:share/accept", carController.AcceptCarInvitation)
+		cars.DELETE("/invitations/:share", carController.DeclineCarInvitation)
 		cars.GET("/:id", carController.GetCar)
-		cars.PUT("/:id", carController.UpdateCar)
-		cars.DELETE("/:id", carController.DeleteCar)
+		cars.PUT("/:id", verified, carController.UpdateCar)
+		cars.PATCH("/:id", verified, carController.PatchCar)
+		cars.DELETE("/:id", verified, carController.DeleteCar)
+		cars.POST("/:id/restore", verified, carController.RestoreCar)
+		cars.GET("/:id/history", carController.GetCarHistory)
+		cars.POST("/:id/history/:revision/revert", verified, carController.RevertCar)
+		cars.GET("/:id/shares", carController.ListCarShares)
+		cars.POST("/:id/shares", verified, carController.ShareCar)
+		cars.PUT("/:id/shares/:share", verified, carController.UpdateCarShare)
+		cars.DELETE("/:id/shares/:share", carController.RevokeCarShare)
+		cars.POST("/:id/publish", verified, carController.PublishCar)
+		cars.POST("/:id/publish/rotate", verified, carController.RotatePublicSlug)
+		cars.DELETE("/:id/publish", verified, carController.UnpublishCar)
+		cars.DELETE("/:id/images/:index", verified, carController.DeleteCarImage)
+		cars.PUT("/:id/images/order", verified, carController.ReorderCarImages)
+		cars.PUT("/:id/images/:index/primary", verified, carController.SetPrimaryCarImage)
 	}
 }
@@ -0,0 +1,372 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCarCRUD(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", true)
+
+	car := s.createCar(alice, map[string]string{
+		"title":       "Family car",
+		"description": "Reliable",
+		"tags":        "red, sedan",
+		"make":        "Honda",
+		"model":       "Accord",
+		"vin":         "1hgcm82633a004352",
+	})
+	if car.UserID != alice.ID || car.Version != 1 || car.VIN != "1HGCM82633A004352" || len(car.Tags) != 2 || car.Tags[1] != "sedan" {
+		t.Fatalf("created car = %+v", car)
+	}
+
+	// Invalid and duplicate VINs are rejected
+	w := s.expect(request{method: "POST", path: "/api/cars", token: alice.token, body: &multipartForm{fields: map[string]string{
+		"title": "Typo", "vin": "1HGCM82633A004353",
+	}}}, http.StatusBadRequest, nil)
+	if code := errorCode(t, w); code != "validation_failed" {
+		t.Errorf("invalid VIN code = %q", code)
+	}
+	w = s.expect(request{method: "POST", path: "/api/cars", token: alice.token, body: &multipartForm{fields: map[string]string{
+		"title": "Again", "vin": "1HGCM82633A004352",
+	}}}, http.StatusConflict, nil)
+	if code := errorCode(t, w); code != "duplicate_vehicle" {
+		t.Errorf("duplicate VIN code = %q", code)
+	}
+
+	// Reading with the current ETag is answered with 304
+	var got testCar
+	w = s.expect(request{method: "GET", path: carPath(car.ID), token: alice.token}, http.StatusOK, &got)
+	etag := w.Header().Get("ETag")
+	if got.Title != "Family car" || etag != `"1"` {
+		t.Errorf("GET = %+v with ETag %s", got, etag)
+	}
+	s.expect(request{method: "GET", path: carPath(car.ID), token: alice.token, headers: map[string]string{"If-None-Match": etag}}, http.StatusNotModified, nil)
+
+	// Other users cannot see the car
+	s.expect(request{method: "GET", path: carPath(car.ID), token: bob.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "PUT", path: carPath(car.ID), token: bob.token, body: &multipartForm{fields: map[string]string{"title": "Mine"}}}, http.StatusForbidden, nil)
+
+	// Updates need the current version when If-Match is sent
+	var updated testCar
+	s.expect(request{method: "PUT", path: carPath(car.ID), token: alice.token, headers: map[string]string{"If-Match": etag},
+		body: &multipartForm{fields: map[string]string{"title": "Old family car"}}}, http.StatusOK, &updated)
+	if updated.Title != "Old family car" || updated.Description != "Reliable" || updated.Version != 2 {
+		t.Errorf("updated car = %+v", updated)
+	}
+	w = s.expect(request{method: "PUT", path: carPath(car.ID), token: alice.token, headers: map[string]string{"If-Match": etag},
+		body: &multipartForm{fields: map[string]string{"title": "Lost update"}}}, http.StatusPreconditionFailed, nil)
+	if code := errorCode(t, w); code != "version_conflict" {
+		t.Errorf("stale If-Match code = %q", code)
+	}
+
+	// PATCH can clear fields
+	var patched testCar
+	s.expect(request{method: "PATCH", path: carPath(car.ID), token: alice.token, body: gin.H{"description": nil, "make": "Acura"}}, http.StatusOK, &patched)
+	if patched.Description != "" || patched.Make != "Acura" || patched.Title != "Old family car" {
+		t.Errorf("patched car = %+v", patched)
+	}
+	jsonPatch := map[string]string{"Content-Type": "application/json-patch+json"}
+	s.expect(request{method: "PATCH", path: carPath(car.ID), token: alice.token, headers: jsonPatch,
+		body: []gin.H{{"op": "replace", "path": "/model", "value": "TLX"}}}, http.StatusOK, &patched)
+	if patched.ModelName != "TLX" || patched.Make != "Acura" {
+		t.Errorf("JSON patched car = %+v", patched)
+	}
+	s.expect(request{method: "PATCH", path: carPath(car.ID), token: alice.token, headers: map[string]string{"Content-Type": "text/plain"},
+		body: gin.H{"make": "Honda"}}, http.StatusUnsupportedMediaType, nil)
+
+	var page carPage
+	s.expect(request{method: "GET", path: "/api/cars", token: alice.token}, http.StatusOK, &page)
+	if page.Pagination.Total != 1 || len(page.Data) != 1 || page.Data[0].ID != car.ID {
+		t.Errorf("alice's cars = %+v", page)
+	}
+	s.expect(request{method: "GET", path: "/api/cars", token: bob.token}, http.StatusOK, &page)
+	if page.Pagination.Total != 0 {
+		t.Errorf("bob's cars = %+v, want none", page)
+	}
+
+	s.expect(request{method: "DELETE", path: carPath(car.ID), token: bob.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: carPath(car.ID), token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: carPath(car.ID), token: alice.token}, http.StatusNotFound, nil)
+}
+
+func TestCarListing(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+
+	for _, title := range []string{"Alpha", "Bravo", "Charlie"} {
+		s.createCar(alice, map[string]string{"title": title, "tags": strings.ToLower(title) + ",fleet"})
+	}
+
+	tests := []struct {
+		query  string
+		titles []string
+		total  int64
+	}{
+		{"", []string{"Charlie", "Bravo", "Alpha"}, 3},
+		{"?sort=title&order=asc", []string{"Alpha", "Bravo", "Charlie"}, 3},
+		{"?sort=title&order=asc&page=2&page_size=2", []string{"Charlie"}, 3},
+		{"?tags_any=alpha,charlie&sort=title&order=asc", []string{"Alpha", "Charlie"}, 2},
+		{"?tags_all=bravo,fleet", []string{"Bravo"}, 1},
+	}
+	for _, tt := range tests {
+		var page carPage
+		s.expect(request{method: "GET", path: "/api/cars" + tt.query, token: alice.token}, http.StatusOK, &page)
+		if titles := carTitles(page.Data); strings.Join(titles, ",") != strings.Join(tt.titles, ",") || page.Pagination.Total != tt.total {
+			t.Errorf("GET /api/cars%s = %v of %d, want %v of %d", tt.query, titles, page.Pagination.Total, tt.titles, tt.total)
+		}
+	}
+
+	s.expect(request{method: "GET", path: "/api/cars?sort=colour", token: alice.token}, http.StatusBadRequest, nil)
+
+	var page carPage
+	s.expect(request{method: "GET", path: "/api/cars?page_size=1000", token: alice.token}, http.StatusOK, &page)
+	if page.Pagination.PageSize != 100 {
+		t.Errorf("page_size = %d, want it capped at 100", page.Pagination.PageSize)
+	}
+}
+
+func TestCarSearch(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", true)
+
+	s.createCar(alice, map[string]string{"title": "Toyota Corolla", "description": "Petrol sedan, one owner"})
+	s.createCar(alice, map[string]string{"title": "Toyota Land Cruiser", "description": "Diesel SUV"})
+	s.createCar(alice, map[string]string{"title": "Honda Civic", "description": "Hybrid hatchback"})
+	s.createCar(bob, map[string]string{"title": "Toyota Yaris"})
+
+	tests := []struct {
+		keyword string
+		titles  []string
+	}{
+		{"toyota", []string{"Toyota Corolla", "Toyota Land Cruiser"}},
+		{"toyota -diesel", []string{"Toyota Corolla"}},
+		{`"land cruiser"`, []string{"Toyota Land Cruiser"}},
+		{"civ*", []string{"Honda Civic"}},
+		{"corolla OR civic", []string{"Honda Civic", "Toyota Corolla"}},
+		{"yaris", nil},
+	}
+	for _, tt := range tests {
+		var page carPage
+		s.expect(request{method: "GET", path: "/api/cars/search?sort=title&order=asc&keyword=" + url.QueryEscape(tt.keyword), token: alice.token}, http.StatusOK, &page)
+		if titles := carTitles(page.Data); strings.Join(titles, ",") != strings.Join(tt.titles, ",") {
+			t.Errorf("search %q = %v, want %v", tt.keyword, titles, tt.titles)
+		}
+	}
+
+	s.expect(request{method: "GET", path: "/api/cars/search", token: alice.token}, http.StatusBadRequest, nil)
+}
+
+func TestCarImages(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+
+	var car testCar
+	s.expect(request{method: "POST", path: "/api/cars", token: alice.token, body: &multipartForm{
+		fields: map[string]string{"title": "Photographed"},
+		images: map[string][]byte{"front.jpg": []byte("front")},
+	}}, http.StatusCreated, &car)
+	s.expect(request{method: "PUT", path: carPath(car.ID), token: alice.token, body: &multipartForm{
+		images: map[string][]byte{"back.png": []byte("back")},
+	}}, http.StatusOK, &car)
+	if len(car.Images) != 2 {
+		t.Fatalf("images = %v, want 2", car.Images)
+	}
+	front, back := car.Images[0], car.Images[1]
+
+	// The local store serves the uploads
+	w := s.expect(request{method: "GET", path: back}, http.StatusOK, nil)
+	if w.Body.String() != "back" {
+		t.Errorf("GET %s = %q", back, w.Body)
+	}
+
+	s.expect(request{method: "PUT", path: carPath(car.ID, "/images/1/primary"), token: alice.token}, http.StatusOK, &car)
+	if car.Images[0] != back || car.Images[1] != front {
+		t.Errorf("after setting the cover image: %v", car.Images)
+	}
+	s.expect(request{method: "PUT", path: carPath(car.ID, "/images/order"), token: alice.token, body: gin.H{"images": []string{front, back}}}, http.StatusOK, &car)
+	if car.Images[0] != front {
+		t.Errorf("after reordering: %v", car.Images)
+	}
+	s.expect(request{method: "PUT", path: carPath(car.ID, "/images/order"), token: alice.token, body: gin.H{"images": []string{front}}}, http.StatusBadRequest, nil)
+	s.expect(request{method: "DELETE", path: carPath(car.ID, "/images/5"), token: alice.token}, http.StatusNotFound, nil)
+
+	s.expect(request{method: "DELETE", path: carPath(car.ID, "/images/0"), token: alice.token}, http.StatusOK, &car)
+	if len(car.Images) != 1 || car.Images[0] != back {
+		t.Errorf("after deleting the first image: %v", car.Images)
+	}
+	if _, err := os.Stat(filepath.Join(s.uploadDir, filepath.Base(front))); !os.IsNotExist(err) {
+		t.Errorf("deleted image is still stored: %v", err)
+	}
+
+	// Deleting the car permanently removes its images
+	s.expect(request{method: "DELETE", path: carPath(car.ID) + "?permanent=true", token: alice.token}, http.StatusOK, nil)
+	if _, err := os.Stat(filepath.Join(s.uploadDir, filepath.Base(back))); !os.IsNotExist(err) {
+		t.Errorf("image of a purged car is still stored: %v", err)
+	}
+
+	// No more than 10 images are taken at once, and none are stored when
+	// there are more
+	tooMany := map[string][]byte{}
+	for i := 0; i < 11; i++ {
+		tooMany[fmt.Sprintf("%d.jpg", i)] = []byte("image")
+	}
+	s.expect(request{method: "POST", path: "/api/cars", token: alice.token, body: &multipartForm{
+		fields: map[string]string{"title": "Overexposed"},
+		images: tooMany,
+	}}, http.StatusBadRequest, nil)
+	if stored, _ := os.ReadDir(s.uploadDir); len(stored) != 0 {
+		t.Errorf("stored %d images of a rejected car", len(stored))
+	}
+	var page carPage
+	s.expect(request{method: "GET", path: "/api/cars", token: alice.token}, http.StatusOK, &page)
+	if page.Pagination.Total != 0 {
+		t.Errorf("cars = %+v, want none", page.Data)
+	}
+}
+
+func TestCarTrash(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", true)
+
+	car := s.createCar(alice, map[string]string{"title": "Deleted car", "vin": "1HGCM82633A004352"})
+	s.expect(request{method: "DELETE", path: carPath(car.ID), token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: carPath(car.ID), token: alice.token}, http.StatusNotFound, nil)
+
+	var trash carPage
+	s.expect(request{method: "GET", path: "/api/cars/trash", token: alice.token}, http.StatusOK, &trash)
+	if len(trash.Data) != 1 || trash.Data[0].ID != car.ID {
+		t.Fatalf("trash = %+v", trash)
+	}
+	s.expect(request{method: "GET", path: "/api/cars/trash", token: bob.token}, http.StatusOK, &trash)
+	if len(trash.Data) != 0 {
+		t.Errorf("bob's trash = %+v, want none", trash)
+	}
+	s.expect(request{method: "POST", path: carPath(car.ID, "/restore"), token: bob.token}, http.StatusForbidden, nil)
+
+	// A car that took the VIN in the meantime blocks the restore
+	other := s.createCar(alice, map[string]string{"title": "Replacement", "vin": "1HGCM82633A004352"})
+	s.expect(request{method: "POST", path: carPath(car.ID, "/restore"), token: alice.token}, http.StatusConflict, nil)
+	s.expect(request{method: "DELETE", path: carPath(other.ID) + "?permanent=true", token: alice.token}, http.StatusOK, nil)
+
+	var restored testCar
+	s.expect(request{method: "POST", path: carPath(car.ID, "/restore"), token: alice.token}, http.StatusOK, &restored)
+	if restored.Title != "Deleted car" {
+		t.Errorf("restored car = %+v", restored)
+	}
+	s.expect(request{method: "POST", path: carPath(car.ID, "/restore"), token: alice.token}, http.StatusBadRequest, nil)
+	s.expect(request{method: "GET", path: carPath(car.ID), token: alice.token}, http.StatusOK, nil)
+
+	// Permanently deleted cars cannot be restored
+	s.expect(request{method: "DELETE", path: carPath(car.ID) + "?permanent=true", token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: "/api/cars/trash", token: alice.token}, http.StatusOK, &trash)
+	if len(trash.Data) != 0 {
+		t.Errorf("trash after purging = %+v, want none", trash)
+	}
+	s.expect(request{method: "POST", path: carPath(car.ID, "/restore"), token: alice.token}, http.StatusNotFound, nil)
+}
+
+func TestCarHistory(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", true)
+
+	car := s.createCar(alice, map[string]string{"title": "First title"})
+	s.expect(request{method: "PUT", path: carPath(car.ID), token: alice.token, body: &multipartForm{fields: map[string]string{"title": "Second title"}}}, http.StatusOK, nil)
+	s.expect(request{method: "DELETE", path: carPath(car.ID), token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: carPath(car.ID, "/restore"), token: alice.token}, http.StatusOK, nil)
+
+	var history struct {
+		Data []struct {
+			ID      uint   `json:"id"`
+			Action  string `json:"action"`
+			Changes map[string]struct {
+				Old interface{} `json:"old"`
+				New interface{} `json:"new"`
+			} `json:"changes"`
+		} `json:"data"`
+	}
+	s.expect(request{method: "GET", path: carPath(car.ID, "/history"), token: bob.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "GET", path: carPath(car.ID, "/history"), token: alice.token}, http.StatusOK, &history)
+	var actions []string
+	for _, revision := range history.Data {
+		actions = append(actions, revision.Action)
+		if len(revision.Changes) == 0 {
+			t.Errorf("%s revision has no changes", revision.Action)
+		}
+	}
+	if strings.Join(actions, ",") != "restore,delete,update,create" {
+		t.Fatalf("history = %v, newest first", actions)
+	}
+	if change := history.Data[2].Changes["title"]; change.Old != "First title" || change.New != "Second title" {
+		t.Errorf("update changed title %v", change)
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok := history.Data[i].Changes["deleted_at"]; !ok {
+			t.Errorf("%s revision does not record deleted_at: %v", history.Data[i].Action, history.Data[i].Changes)
+		}
+	}
+
+	// Reverting to the create revision brings the first title back
+	create := fmt.Sprint(history.Data[3].ID)
+	s.expect(request{method: "POST", path: carPath(car.ID, "/history/", create, "/revert"), token: bob.token}, http.StatusForbidden, nil)
+	var reverted testCar
+	s.expect(request{method: "POST", path: carPath(car.ID, "/history/", create, "/revert"), token: alice.token}, http.StatusOK, &reverted)
+	if reverted.Title != "First title" {
+		t.Errorf("reverted car = %+v", reverted)
+	}
+	s.expect(request{method: "GET", path: carPath(car.ID, "/history"), token: alice.token}, http.StatusOK, &history)
+	if len(history.Data) != 5 || history.Data[0].Action != "revert" {
+		t.Errorf("history after reverting = %+v", history.Data)
+	}
+}
+
+func TestIdempotentCarCreation(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+
+	create := request{method: "POST", path: "/api/cars", token: alice.token, headers: map[string]string{"Idempotency-Key": "create-1"},
+		body: &multipartForm{fields: map[string]string{"title": "Once"}}}
+	var first, retried testCar
+	s.expect(create, http.StatusCreated, &first)
+	w := s.expect(create, http.StatusCreated, &retried)
+	if retried.ID != first.ID || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry = %+v, want the replayed %+v", retried, first)
+	}
+
+	create.body = &multipartForm{fields: map[string]string{"title": "Twice"}}
+	s.expect(create, http.StatusUnprocessableEntity, nil)
+
+	// The same request in an organization is a different request
+	var org struct {
+		ID uint `json:"id"`
+	}
+	s.expect(request{method: "POST", path: "/api/organizations", token: alice.token, body: gin.H{"name": "Acme Fleet"}}, http.StatusCreated, &org)
+	create.body = &multipartForm{fields: map[string]string{"title": "Once"}}
+	create.headers["X-Organization-ID"] = fmt.Sprint(org.ID)
+	w = s.expect(create, http.StatusUnprocessableEntity, nil)
+	if code := errorCode(t, w); code != "idempotency_key_reused" {
+		t.Errorf("key reused in an organization: code %q", code)
+	}
+
+	var page carPage
+	s.expect(request{method: "GET", path: "/api/cars", token: alice.token}, http.StatusOK, &page)
+	if page.Pagination.Total != 1 {
+		t.Errorf("cars = %+v, want one", page.Data)
+	}
+	s.expect(request{method: "GET", path: "/api/cars", token: alice.token, headers: create.headers}, http.StatusOK, &page)
+	if page.Pagination.Total != 0 {
+		t.Errorf("fleet cars = %+v, want none", page.Data)
+	}
+}
@@ -0,0 +1,119 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testShare is the part of a car share the tests look at
+type testShare struct {
+	ID       uint   `json:"id"`
+	CarID    uint   `json:"car_id"`
+	Email    string `json:"email"`
+	UserID   *uint  `json:"user_id"`
+	Role     string `json:"role"`
+	Username string `json:"username"`
+}
+
+func invitationPath(id uint, rest string) string {
+	return fmt.Sprintf("/api/cars/invitations/%d%s", id, rest)
+}
+
+func TestCarSharing(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", false)
+	carol := s.newUser("carol", true)
+	car := s.createCar(alice, map[string]string{"title": "Shared car"})
+	sharesPath := carPath(car.ID, "/shares")
+
+	// Sharing answers the same whether or not the address has an account
+	var share, unknownShare testShare
+	s.expect(request{method: "POST", path: sharesPath, token: alice.token, body: gin.H{"email": "Bob@Example.com", "role": "viewer"}}, http.StatusCreated, &share)
+	s.expect(request{method: "POST", path: sharesPath, token: alice.token, body: gin.H{"email": "dave@example.com", "role": "viewer"}}, http.StatusCreated, &unknownShare)
+	if share.Email != "bob@example.com" || share.UserID != nil || unknownShare.UserID != nil {
+		t.Fatalf("shares = %+v and %+v, want pending shares by email", share, unknownShare)
+	}
+	if s.mailCount("bob@example.com") != 1 || s.mailCount("dave@example.com") != 1 {
+		t.Error("invitations were not emailed to both addresses")
+	}
+	// The subject is fixed so that a username cannot add mail headers
+	if mail := s.mailTo("bob@example.com"); !strings.Contains(mail, "Subject: A car was shared with you on Car Management\n") || !strings.Contains(mail, "alice invited you") {
+		t.Errorf("invitation = %q", mail)
+	}
+
+	s.expect(request{method: "POST", path: sharesPath, token: alice.token, body: gin.H{"email": "BOB@example.com", "role": "editor"}}, http.StatusConflict, nil)
+	s.expect(request{method: "POST", path: sharesPath, token: alice.token, body: gin.H{"email": "alice@example.com", "role": "viewer"}}, http.StatusBadRequest, nil)
+	s.expect(request{method: "POST", path: sharesPath, token: alice.token, body: gin.H{"email": "erin@example.com", "role": "owner"}}, http.StatusBadRequest, nil)
+	s.expect(request{method: "POST", path: sharesPath, token: carol.token, body: gin.H{"email": "erin@example.com", "role": "viewer"}}, http.StatusForbidden, nil)
+
+	// The invitation is only for the user with that email, once verified
+	var invitations []testShare
+	s.expect(request{method: "GET", path: "/api/cars/invitations", token: bob.token}, http.StatusOK, &invitations)
+	if len(invitations) != 1 || invitations[0].ID != share.ID {
+		t.Fatalf("bob's invitations = %+v", invitations)
+	}
+	s.expect(request{method: "POST", path: invitationPath(share.ID, "/accept"), token: carol.token}, http.StatusNotFound, nil)
+	w := s.expect(request{method: "POST", path: invitationPath(share.ID, "/accept"), token: bob.token}, http.StatusForbidden, nil)
+	if code := errorCode(t, w); code != "email_not_verified" {
+		t.Errorf("unverified accept code = %q", code)
+	}
+	s.expect(request{method: "GET", path: carPath(car.ID), token: bob.token}, http.StatusForbidden, nil)
+
+	s.db.Exec("UPDATE users SET verified = ? WHERE id = ?", true, bob.ID)
+	s.expect(request{method: "POST", path: invitationPath(share.ID, "/accept"), token: bob.token}, http.StatusOK, &share)
+	if share.UserID == nil || *share.UserID != bob.ID {
+		t.Fatalf("accepted share = %+v", share)
+	}
+
+	// Viewers can see the car but not change it
+	s.expect(request{method: "GET", path: carPath(car.ID), token: bob.token}, http.StatusOK, nil)
+	s.expect(request{method: "PUT", path: carPath(car.ID), token: bob.token, body: &multipartForm{fields: map[string]string{"title": "Viewed"}}}, http.StatusForbidden, nil)
+
+	var shared carPage
+	s.expect(request{method: "GET", path: "/api/cars/shared", token: bob.token}, http.StatusOK, &shared)
+	if len(shared.Data) != 1 || shared.Data[0].ID != car.ID {
+		t.Errorf("bob's shared cars = %+v", shared)
+	}
+	s.expect(request{method: "GET", path: "/api/cars?scope=all", token: bob.token}, http.StatusOK, &shared)
+	if len(shared.Data) != 1 {
+		t.Errorf("bob's cars with scope=all = %+v", shared)
+	}
+
+	var shares []testShare
+	s.expect(request{method: "GET", path: sharesPath, token: alice.token}, http.StatusOK, &shares)
+	if len(shares) != 2 {
+		t.Fatalf("shares = %+v, want 2", shares)
+	}
+	for _, sh := range shares {
+		if sh.ID == share.ID && sh.Username != "bob" || sh.ID == unknownShare.ID && (sh.Username != "" || sh.Email != "dave@example.com") {
+			t.Errorf("share = %+v", sh)
+		}
+	}
+	s.expect(request{method: "GET", path: sharesPath, token: bob.token}, http.StatusForbidden, nil)
+
+	// Editors can change the car
+	s.expect(request{method: "PUT", path: sharesPath + fmt.Sprintf("/%d", share.ID), token: alice.token, body: gin.H{"role": "editor"}}, http.StatusOK, nil)
+	s.expect(request{method: "PUT", path: carPath(car.ID), token: bob.token, body: &multipartForm{fields: map[string]string{"title": "Edited"}}}, http.StatusOK, nil)
+	s.expect(request{method: "DELETE", path: carPath(car.ID), token: bob.token}, http.StatusForbidden, nil)
+
+	// The shared user can leave the share
+	s.expect(request{method: "DELETE", path: sharesPath + fmt.Sprintf("/%d", share.ID), token: bob.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: carPath(car.ID), token: bob.token}, http.StatusForbidden, nil)
+
+	// An address without an account can accept once it signs up
+	dave := s.newUser("dave", true)
+	s.expect(request{method: "GET", path: "/api/cars/invitations", token: dave.token}, http.StatusOK, &invitations)
+	if len(invitations) != 1 || invitations[0].ID != unknownShare.ID {
+		t.Fatalf("dave's invitations = %+v", invitations)
+	}
+	s.expect(request{method: "POST", path: invitationPath(unknownShare.ID, "/accept"), token: dave.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: carPath(car.ID), token: dave.token}, http.StatusOK, nil)
+
+	// Accepted shares are not invitations for anyone else
+	s.expect(request{method: "DELETE", path: invitationPath(unknownShare.ID, ""), token: carol.token}, http.StatusNotFound, nil)
+}
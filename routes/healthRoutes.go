@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/akashkumar7902/car-management-backend/controllers"
+	"github.com/akashkumar7902/car-management-backend/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func HealthRoutes(r *gin.Engine, db *gorm.DB, store storage.ImageStore) {
+	healthController := controllers.HealthController{
+		DB:    db,
+		Store: store,
+	}
+
+	r.GET("/healthz", healthController.Healthz)
+	r.GET("/readyz", healthController.Readyz)
+}
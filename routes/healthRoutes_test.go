@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestHealthProbes(t *testing.T) {
+	s := newTestServer(t)
+
+	var health struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	s.expect(request{method: "GET", path: "/healthz"}, http.StatusOK, &health)
+	if health.Status != "ok" {
+		t.Errorf("healthz = %+v", health)
+	}
+	s.expect(request{method: "GET", path: "/readyz"}, http.StatusOK, &health)
+	if health.Checks["database"] != "ok" || health.Checks["storage"] != "ok" {
+		t.Errorf("readyz = %+v", health)
+	}
+
+	// Losing the upload directory makes the server unready but still alive
+	if err := os.RemoveAll(s.uploadDir); err != nil {
+		t.Fatal(err)
+	}
+	s.expect(request{method: "GET", path: "/readyz"}, http.StatusServiceUnavailable, &health)
+	if health.Status != "unavailable" || health.Checks["storage"] != "unavailable" || health.Checks["database"] != "ok" {
+		t.Errorf("readyz without storage = %+v", health)
+	}
+	s.expect(request{method: "GET", path: "/healthz"}, http.StatusOK, nil)
+}
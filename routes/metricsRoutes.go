@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/metrics"
+	"github.com/akashkumar7902/car-management-backend/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func MetricsRoutes(r *gin.Engine, cfg config.Config) {
+	if !cfg.MetricsEnabled {
+		return
+	}
+	r.GET("/metrics", middlewares.MetricsToken(cfg.MetricsToken), gin.WrapH(metrics.Handler()))
+}
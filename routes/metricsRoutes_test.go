@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	s := newTestServer(t)
+	s.expect(request{method: "GET", path: "/api/cars/1"}, http.StatusUnauthorized, nil)
+
+	s.expect(request{method: "GET", path: "/metrics"}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "GET", path: "/metrics", token: "wrong"}, http.StatusUnauthorized, nil)
+
+	w := s.expect(request{method: "GET", path: "/metrics", token: "metrics-secret"}, http.StatusOK, nil)
+	if body := w.Body.String(); !strings.Contains(body, `route="/api/cars/:id"`) {
+		t.Errorf("metrics do not record requests by route:\n%s", body)
+	}
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/controllers"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/middlewares"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func OrganizationRoutes(r *gin.Engine, db *gorm.DB, repos repositories.Repositories, cfg config.Config, m mailer.Mailer, limiter ratelimit.Store) {
+	organizationController := controllers.OrganizationController{
+		DB:     db,
+		Repos:  repos,
+		Cfg:    cfg,
+		Mailer: m,
+	}
+
+	authMiddleware := middlewares.AuthMiddleware(db, cfg)
+	verified := middlewares.RequireVerifiedEmail(cfg)
+	idempotency := middlewares.Idempotency(db, cfg)
+	rateLimit := middlewares.RateLimit(limiter, "api", cfg.APIRateLimit, middlewares.ByUser)
+
+	organizations := r.Group("/api/organizations").Use(authMiddleware, rateLimit, idempotency)
+	{
+		organizations.POST("", verified, organizationController.CreateOrganization)
+		organizations.GET("", organizationController.ListOrganizations)
+		organizations.PUT("/active", organizationController.SwitchOrganization)
+		organizations.GET("/invitations", organizationController.ListMyInvitations)
+		organizations.POST("/invitations/:invitation/accept", organizationController.AcceptInvitation)
+		organizations.DELETE("/invitations/:invitation", organizationController.DeclineInvitation)
+		organizations.GET("/:id", organizationController.GetOrganization)
+		organizations.PUT("/:id", verified, organizationController.UpdateOrganization)
+		organizations.DELETE("/:id", verified, organizationController.DeleteOrganization)
+		organizations.GET("/:id/members", organizationController.ListMembers)
+		organizations.PUT("/:id/members/:user", verified, organizationController.UpdateMember)
+		organizations.DELETE("/:id/members/:user", organizationController.RemoveMember)
+		organizations.GET("/:id/invitations", organizationController.ListInvitations)
+		organizations.POST("/:id/invitations", verified, organizationController.InviteMember)
+		organizations.DELETE("/:id/invitations/:invitation", organizationController.RevokeInvitation)
+	}
+}
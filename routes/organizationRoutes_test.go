@@ -0,0 +1,216 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestOrganizations(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", true)
+	carol := s.newUser("carol", true)
+	personal := s.createCar(alice, map[string]string{"title": "Personal car"})
+
+	var org struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	s.expect(request{method: "POST", path: "/api/organizations", token: alice.token, body: gin.H{"name": "Acme Fleet"}}, http.StatusCreated, &org)
+	if org.Name != "Acme Fleet" || org.Role != "owner" {
+		t.Fatalf("organization = %+v", org)
+	}
+	orgPath := fmt.Sprintf("/api/organizations/%d", org.ID)
+	inOrg := map[string]string{"X-Organization-ID": fmt.Sprint(org.ID)}
+
+	// Bob is invited as a driver by email and accepts
+	var invitation struct {
+		ID    uint   `json:"id"`
+		Email string `json:"email"`
+	}
+	s.expect(request{method: "POST", path: orgPath + "/invitations", token: alice.token, body: gin.H{"email": "BOB@example.com", "role": "driver"}}, http.StatusCreated, &invitation)
+	if invitation.Email != "bob@example.com" || s.mailCount("bob@example.com") != 1 {
+		t.Fatalf("invitation = %+v, want one emailed to bob@example.com", invitation)
+	}
+	// The subject is fixed so that an organization name cannot add mail
+	// headers
+	if mail := s.mailTo("bob@example.com"); !strings.Contains(mail, "Subject: You are invited to an organization on Car Management\n") || !strings.Contains(mail, "join Acme Fleet") {
+		t.Errorf("invitation = %q", mail)
+	}
+	invitationPath := fmt.Sprintf("/api/organizations/invitations/%d", invitation.ID)
+	s.expect(request{method: "POST", path: invitationPath + "/accept", token: carol.token}, http.StatusNotFound, nil)
+	s.expect(request{method: "POST", path: invitationPath + "/accept", token: bob.token}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: orgPath + "/invitations", token: alice.token, body: gin.H{"email": "bob@example.com", "role": "driver"}}, http.StatusConflict, nil)
+
+	var members []struct {
+		UserID uint   `json:"user_id"`
+		Role   string `json:"role"`
+	}
+	s.expect(request{method: "GET", path: orgPath + "/members", token: bob.token}, http.StatusOK, &members)
+	if len(members) != 2 {
+		t.Errorf("members = %+v, want alice and bob", members)
+	}
+	s.expect(request{method: "GET", path: orgPath, token: carol.token}, http.StatusNotFound, nil)
+
+	// Drivers cannot invite
+	s.expect(request{method: "POST", path: orgPath + "/invitations", token: bob.token, body: gin.H{"email": "carol@example.com", "role": "driver"}}, http.StatusForbidden, nil)
+
+	// Cars added in the organization join its fleet
+	var fleetCar testCar
+	s.expect(request{method: "POST", path: "/api/cars", token: alice.token, headers: inOrg, body: &multipartForm{fields: map[string]string{"title": "Van 1"}}}, http.StatusCreated, &fleetCar)
+	if fleetCar.OrganizationID == nil || *fleetCar.OrganizationID != org.ID {
+		t.Fatalf("fleet car = %+v", fleetCar)
+	}
+
+	// Switching the session to the organization selects its fleet
+	var switched struct {
+		Token          string `json:"token"`
+		OrganizationID uint   `json:"organization_id"`
+	}
+	s.expect(request{method: "PUT", path: "/api/organizations/active", token: bob.token, body: gin.H{"organization_id": org.ID}}, http.StatusOK, &switched)
+	if switched.OrganizationID != org.ID || switched.Token == "" {
+		t.Fatalf("switch = %+v", switched)
+	}
+	var page carPage
+	s.expect(request{method: "GET", path: "/api/cars", token: switched.Token}, http.StatusOK, &page)
+	if len(page.Data) != 1 || page.Data[0].ID != fleetCar.ID {
+		t.Errorf("bob's fleet = %+v", page.Data)
+	}
+	s.expect(request{method: "GET", path: "/api/cars", token: switched.Token, headers: map[string]string{"X-Organization-ID": "0"}}, http.StatusOK, &page)
+	if len(page.Data) != 0 {
+		t.Errorf("bob's personal cars = %+v, want none", page.Data)
+	}
+	s.expect(request{method: "PUT", path: "/api/organizations/active", token: carol.token, body: gin.H{"organization_id": org.ID}}, http.StatusForbidden, nil)
+
+	// Drivers update fleet cars but do not add or delete them
+	s.expect(request{method: "PUT", path: carPath(fleetCar.ID), token: switched.Token, body: &multipartForm{fields: map[string]string{"mileage": "1200"}}}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: "/api/cars", token: switched.Token, body: &multipartForm{fields: map[string]string{"title": "Van 2"}}}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: carPath(fleetCar.ID), token: switched.Token}, http.StatusForbidden, nil)
+
+	// Personal cars are out of reach in the organization, even for their owner
+	s.expect(request{method: "GET", path: carPath(personal.ID), token: alice.token, headers: inOrg}, http.StatusForbidden, nil)
+	s.expect(request{method: "GET", path: carPath(fleetCar.ID), token: carol.token, headers: inOrg}, http.StatusForbidden, nil)
+
+	// Organizations with cars cannot be deleted
+	s.expect(request{method: "DELETE", path: orgPath, token: bob.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: orgPath, token: alice.token}, http.StatusConflict, nil)
+	s.expect(request{method: "DELETE", path: carPath(fleetCar.ID) + "?permanent=true", token: alice.token, headers: inOrg}, http.StatusOK, nil)
+
+	// Removed members lose access at once
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("%s/members/%d", orgPath, bob.ID), token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: "/api/cars", token: switched.Token}, http.StatusForbidden, nil)
+
+	s.expect(request{method: "DELETE", path: orgPath, token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: orgPath, token: alice.token}, http.StatusNotFound, nil)
+}
+
+func TestOrganizationMembers(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", true)
+	carol := s.newUser("carol", true)
+	dave := s.newUser("dave", false)
+
+	var org struct {
+		ID uint `json:"id"`
+	}
+	s.expect(request{method: "POST", path: "/api/organizations", token: alice.token, body: gin.H{"name": "Acme Fleet"}}, http.StatusCreated, &org)
+	orgPath := fmt.Sprintf("/api/organizations/%d", org.ID)
+	memberPath := func(user testUser) string {
+		return fmt.Sprintf("%s/members/%d", orgPath, user.ID)
+	}
+	invite := func(inviter testUser, email, role string, status int) uint {
+		var invitation struct {
+			ID uint `json:"id"`
+		}
+		var out interface{}
+		if status == http.StatusCreated {
+			out = &invitation
+		}
+		s.expect(request{method: "POST", path: orgPath + "/invitations", token: inviter.token, body: gin.H{"email": email, "role": role}}, status, out)
+		return invitation.ID
+	}
+	myInvitations := func(user testUser) []uint {
+		var invitations []struct {
+			ID uint `json:"id"`
+		}
+		s.expect(request{method: "GET", path: "/api/organizations/invitations", token: user.token}, http.StatusOK, &invitations)
+		var ids []uint
+		for _, invitation := range invitations {
+			ids = append(ids, invitation.ID)
+		}
+		return ids
+	}
+
+	bobInvite := invite(alice, "bob@example.com", "manager", http.StatusCreated)
+	carolInvite := invite(alice, "carol@example.com", "driver", http.StatusCreated)
+	daveInvite := invite(alice, "dave@example.com", "driver", http.StatusCreated)
+	invite(alice, "erin@example.com", "boss", http.StatusBadRequest)
+
+	var pending []struct {
+		ID uint `json:"id"`
+	}
+	s.expect(request{method: "GET", path: orgPath + "/invitations", token: alice.token}, http.StatusOK, &pending)
+	if len(pending) != 3 {
+		t.Errorf("pending invitations = %+v, want 3", pending)
+	}
+	s.expect(request{method: "GET", path: orgPath + "/invitations", token: bob.token}, http.StatusNotFound, nil)
+
+	// Each user sees and answers only their own invitations
+	if ids := myInvitations(bob); len(ids) != 1 || ids[0] != bobInvite {
+		t.Errorf("bob's invitations = %v", ids)
+	}
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("/api/organizations/invitations/%d", carolInvite), token: bob.token}, http.StatusNotFound, nil)
+	s.expect(request{method: "POST", path: fmt.Sprintf("/api/organizations/invitations/%d/accept", bobInvite), token: bob.token}, http.StatusOK, nil)
+	s.expect(request{method: "POST", path: fmt.Sprintf("/api/organizations/invitations/%d/accept", carolInvite), token: carol.token}, http.StatusOK, nil)
+	w := s.expect(request{method: "POST", path: fmt.Sprintf("/api/organizations/invitations/%d/accept", daveInvite), token: dave.token}, http.StatusForbidden, nil)
+	if code := errorCode(t, w); code != "email_not_verified" {
+		t.Errorf("unverified accept code = %q", code)
+	}
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("/api/organizations/invitations/%d", daveInvite), token: dave.token}, http.StatusOK, nil)
+	if ids := myInvitations(dave); len(ids) != 0 {
+		t.Errorf("dave's invitations after declining = %v", ids)
+	}
+
+	var orgs []struct {
+		ID   uint   `json:"id"`
+		Role string `json:"role"`
+	}
+	s.expect(request{method: "GET", path: "/api/organizations", token: carol.token}, http.StatusOK, &orgs)
+	if len(orgs) != 1 || orgs[0].ID != org.ID || orgs[0].Role != "driver" {
+		t.Errorf("carol's organizations = %+v", orgs)
+	}
+
+	// Managers invite managers and drivers, and manage pending invitations
+	invite(bob, "erin@example.com", "owner", http.StatusForbidden)
+	erinInvite := invite(bob, "erin@example.com", "driver", http.StatusCreated)
+	s.expect(request{method: "GET", path: orgPath + "/invitations", token: carol.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("%s/invitations/%d", orgPath, erinInvite), token: carol.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("%s/invitations/%d", orgPath, erinInvite), token: bob.token}, http.StatusOK, nil)
+	s.expect(request{method: "DELETE", path: fmt.Sprintf("%s/invitations/%d", orgPath, erinInvite), token: bob.token}, http.StatusNotFound, nil)
+
+	// Only owners rename the organization and change roles
+	s.expect(request{method: "PUT", path: orgPath, token: bob.token, body: gin.H{"name": "Bob's Fleet"}}, http.StatusForbidden, nil)
+	s.expect(request{method: "PUT", path: orgPath, token: alice.token, body: gin.H{"name": "Acme Logistics"}}, http.StatusOK, nil)
+	s.expect(request{method: "PUT", path: memberPath(carol), token: bob.token, body: gin.H{"role": "manager"}}, http.StatusForbidden, nil)
+	s.expect(request{method: "PUT", path: memberPath(carol), token: alice.token, body: gin.H{"role": "boss"}}, http.StatusBadRequest, nil)
+	s.expect(request{method: "PUT", path: memberPath(carol), token: alice.token, body: gin.H{"role": "manager"}}, http.StatusOK, nil)
+	s.expect(request{method: "PUT", path: memberPath(dave), token: alice.token, body: gin.H{"role": "manager"}}, http.StatusNotFound, nil)
+
+	// Managers cannot remove other managers, but anyone can leave
+	s.expect(request{method: "DELETE", path: memberPath(carol), token: bob.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: memberPath(carol), token: carol.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: orgPath, token: carol.token}, http.StatusNotFound, nil)
+
+	// The organization always keeps an owner
+	s.expect(request{method: "PUT", path: memberPath(alice), token: alice.token, body: gin.H{"role": "manager"}}, http.StatusConflict, nil)
+	s.expect(request{method: "DELETE", path: memberPath(alice), token: alice.token}, http.StatusConflict, nil)
+	s.expect(request{method: "PUT", path: memberPath(bob), token: alice.token, body: gin.H{"role": "owner"}}, http.StatusOK, nil)
+	s.expect(request{method: "DELETE", path: memberPath(alice), token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: orgPath, token: bob.token}, http.StatusOK, nil)
+}
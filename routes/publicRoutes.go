@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/controllers"
+	"github.com/akashkumar7902/car-management-backend/middlewares"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+func PublicRoutes(r *gin.Engine, repos repositories.Repositories, cfg config.Config, limiter ratelimit.Store) {
+	publicController := controllers.PublicController{
+		Repos: repos,
+	}
+
+	rateLimit := middlewares.RateLimit(limiter, "public", cfg.PublicRateLimit, middlewares.ByIP)
+
+	public := r.Group("/api/public").Use(rateLimit)
+	{
+		public.GET("/cars", publicController.ListPublicCars)
+		public.GET("/cars/search", publicController.SearchPublicCars)
+		public.GET("/cars/:slug", publicController.GetPublicCar)
+	}
+}
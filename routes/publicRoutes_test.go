@@ -0,0 +1,66 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPublishedCars(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.newUser("alice", true)
+	bob := s.newUser("bob", true)
+	car := s.createCar(alice, map[string]string{"title": "Sedan for sale", "description": "One careful owner"})
+	private := s.createCar(alice, map[string]string{"title": "Private sedan"})
+
+	s.expect(request{method: "POST", path: carPath(car.ID, "/publish")}, http.StatusUnauthorized, nil)
+	s.expect(request{method: "POST", path: carPath(car.ID, "/publish"), token: bob.token}, http.StatusForbidden, nil)
+
+	var published testCar
+	s.expect(request{method: "POST", path: carPath(car.ID, "/publish"), token: alice.token}, http.StatusOK, &published)
+	if published.PublicSlug == nil {
+		t.Fatalf("published car = %+v", published)
+	}
+	slug := *published.PublicSlug
+
+	// The public pages need no session and only show published cars
+	var page struct {
+		Slug  string `json:"slug"`
+		Title string `json:"title"`
+	}
+	s.expect(request{method: "GET", path: "/api/public/cars/" + slug}, http.StatusOK, &page)
+	if page.Slug != slug || page.Title != "Sedan for sale" {
+		t.Errorf("public page = %+v", page)
+	}
+	var list struct {
+		Data []struct {
+			Title string `json:"title"`
+		} `json:"data"`
+	}
+	s.expect(request{method: "GET", path: "/api/public/cars"}, http.StatusOK, &list)
+	if len(list.Data) != 1 || list.Data[0].Title != "Sedan for sale" {
+		t.Errorf("public cars = %+v", list.Data)
+	}
+	s.expect(request{method: "GET", path: "/api/public/cars/search?keyword=sedan"}, http.StatusOK, &list)
+	if len(list.Data) != 1 || list.Data[0].Title != "Sedan for sale" {
+		t.Errorf("public search = %+v", list.Data)
+	}
+	s.expect(request{method: "GET", path: "/api/public/cars/search"}, http.StatusBadRequest, nil)
+
+	// Rotating the slug retires the old link
+	s.expect(request{method: "POST", path: carPath(private.ID, "/publish/rotate"), token: alice.token}, http.StatusBadRequest, nil)
+	var rotated testCar
+	s.expect(request{method: "POST", path: carPath(car.ID, "/publish/rotate"), token: alice.token}, http.StatusOK, &rotated)
+	if rotated.PublicSlug == nil || *rotated.PublicSlug == slug {
+		t.Fatalf("rotated car = %+v", rotated)
+	}
+	s.expect(request{method: "GET", path: "/api/public/cars/" + slug}, http.StatusNotFound, nil)
+	s.expect(request{method: "GET", path: "/api/public/cars/" + *rotated.PublicSlug}, http.StatusOK, nil)
+
+	s.expect(request{method: "DELETE", path: carPath(car.ID, "/publish"), token: bob.token}, http.StatusForbidden, nil)
+	s.expect(request{method: "DELETE", path: carPath(car.ID, "/publish"), token: alice.token}, http.StatusOK, nil)
+	s.expect(request{method: "GET", path: "/api/public/cars/" + *rotated.PublicSlug}, http.StatusNotFound, nil)
+	s.expect(request{method: "GET", path: "/api/public/cars"}, http.StatusOK, &list)
+	if len(list.Data) != 0 {
+		t.Errorf("public cars after unpublishing = %+v", list.Data)
+	}
+}
@@ -0,0 +1,332 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/akashkumar7902/car-management-backend/database"
+	"github.com/akashkumar7902/car-management-backend/mailer"
+	"github.com/akashkumar7902/car-management-backend/middlewares"
+	"github.com/akashkumar7902/car-management-backend/models"
+	"github.com/akashkumar7902/car-management-backend/ratelimit"
+	"github.com/akashkumar7902/car-management-backend/repositories"
+	"github.com/akashkumar7902/car-management-backend/storage"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testPassword is the password of every user made by newUser
+const testPassword = "password123"
+
+// testServer is the API served from an in-memory SQLite database, with
+// images stored in a temporary directory and email written to a file
+type testServer struct {
+	t         *testing.T
+	router    *gin.Engine
+	db        *gorm.DB
+	cfg       config.Config
+	mailLog   string
+	uploadDir string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.Config{
+		JWTSecret:               "test-secret",
+		AccessTokenTTL:          15 * time.Minute,
+		RefreshTokenTTL:         24 * time.Hour,
+		SessionMaxAge:           7 * 24 * time.Hour,
+		AppURL:                  "http://app.test",
+		MailDriver:              "log",
+		MailLogPath:             filepath.Join(dir, "mail.log"),
+		PasswordResetTTL:        time.Hour,
+		EmailVerificationTTL:    time.Hour,
+		TOTPIssuer:              "Car Management",
+		TwoFactorChallengeTTL:   5 * time.Minute,
+		StorageDriver:           "local",
+		UploadDir:               filepath.Join(dir, "uploads"),
+		UploadBaseURL:           "/uploads",
+		IdempotencyKeyTTL:       time.Hour,
+		OrgInvitationTTL:        24 * time.Hour,
+		LoginLockoutThreshold:   3,
+		LoginLockoutDuration:    time.Minute,
+		LoginLockoutMaxDuration: time.Hour,
+		LoginFailureWindow:      time.Hour,
+		MetricsEnabled:          true,
+		MetricsToken:            "metrics-secret",
+	}
+
+	db, err := database.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos, err := repositories.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := storage.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := mailer.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	r.Use(middlewares.RequestID(), middlewares.Metrics(), middlewares.Recovery())
+	r.Static("/uploads", cfg.UploadDir)
+	limiter := ratelimit.NewMemoryStore()
+	AuthRoutes(r, db, repos, cfg, m, limiter)
+	CarRoutes(r, db, repos, cfg, store, m, limiter)
+	OrganizationRoutes(r, db, repos, cfg, m, limiter)
+	PublicRoutes(r, repos, cfg, limiter)
+	AdminRoutes(r, db, repos, cfg, store, limiter)
+	MetricsRoutes(r, cfg)
+	HealthRoutes(r, db, store)
+
+	return &testServer{t: t, router: r, db: db, cfg: cfg, mailLog: cfg.MailLogPath, uploadDir: cfg.UploadDir}
+}
+
+// request is a request to the test server. Body is encoded as JSON unless
+// it is a *multipartForm.
+type request struct {
+	method  string
+	path    string
+	token   string
+	body    interface{}
+	headers map[string]string
+	// ip is the client address, 192.0.2.1 if empty
+	ip string
+}
+
+// multipartForm is a form with image files, keyed by file name
+type multipartForm struct {
+	fields map[string]string
+	images map[string][]byte
+}
+
+func (s *testServer) do(req request) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var body io.Reader
+	contentType := ""
+	switch b := req.body.(type) {
+	case nil:
+	case *multipartForm:
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for name, value := range b.fields {
+			w.WriteField(name, value)
+		}
+		for filename, data := range b.images {
+			part, err := w.CreateFormFile("images", filename)
+			if err != nil {
+				s.t.Fatal(err)
+			}
+			part.Write(data)
+		}
+		w.Close()
+		body, contentType = &buf, w.FormDataContentType()
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		body, contentType = bytes.NewReader(data), "application/json"
+	}
+
+	r := httptest.NewRequest(req.method, req.path, body)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	if req.token != "" {
+		r.Header.Set("Authorization", "Bearer "+req.token)
+	}
+	for name, value := range req.headers {
+		r.Header.Set(name, value)
+	}
+	if req.ip != "" {
+		r.RemoteAddr = req.ip + ":1234"
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+// expect sends the request, fails the test unless it gets the status and
+// decodes the response into out, if given
+func (s *testServer) expect(req request, status int, out interface{}) *httptest.ResponseRecorder {
+	s.t.Helper()
+	w := s.do(req)
+	if w.Code != status {
+		s.t.Fatalf("%s %s: status %d, want %d: %s", req.method, req.path, w.Code, status, w.Body)
+	}
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("%s %s: decoding %s: %v", req.method, req.path, w.Body, err)
+		}
+	}
+	return w
+}
+
+// testUser is a user of the test server with a session
+type testUser struct {
+	models.User
+	token string
+}
+
+// newUser creates a user directly in the database and logs them in.
+// Passwords are hashed at the lowest cost to keep the tests fast.
+func (s *testServer) newUser(username string, verified bool) testUser {
+	s.t.Helper()
+	return s.newUserWithRole(username, verified, models.RoleUser)
+}
+
+// newUserWithRole is newUser for a user with the given role
+func (s *testServer) newUserWithRole(username string, verified bool, role string) testUser {
+	s.t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	user := models.User{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: string(hash),
+		Verified: verified,
+		Role:     role,
+	}
+	if err := s.db.Create(&user).Error; err != nil {
+		s.t.Fatal(err)
+	}
+	return testUser{User: user, token: s.login(user.Email)}
+}
+
+// login logs in with the test password and returns the access token
+func (s *testServer) login(email string) string {
+	s.t.Helper()
+	var out struct {
+		Token string `json:"token"`
+	}
+	s.expect(request{method: "POST", path: "/api/users/login", body: gin.H{"email": email, "password": testPassword}}, http.StatusOK, &out)
+	return out.Token
+}
+
+// mailTo returns the last email sent to the address, failing the test if
+// there is none
+func (s *testServer) mailTo(address string) string {
+	s.t.Helper()
+	data, err := os.ReadFile(s.mailLog)
+	if err != nil && !os.IsNotExist(err) {
+		s.t.Fatal(err)
+	}
+	entries := strings.Split(string(data), "\n\n[")
+	for i := len(entries) - 1; i >= 0; i-- {
+		if strings.Contains(entries[i], "To: "+address+"\n") {
+			return entries[i]
+		}
+	}
+	s.t.Fatalf("no email sent to %s", address)
+	return ""
+}
+
+// mailCount returns how many emails were sent to the address
+func (s *testServer) mailCount(address string) int {
+	data, _ := os.ReadFile(s.mailLog)
+	return strings.Count(string(data), "To: "+address+"\n")
+}
+
+var tokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// tokenIn returns the token of the link in an email
+func tokenIn(t *testing.T, mail string) string {
+	t.Helper()
+	match := tokenPattern.FindStringSubmatch(mail)
+	if match == nil {
+		t.Fatalf("no token in email:\n%s", mail)
+	}
+	return match[1]
+}
+
+// testCar is the part of a car the tests look at
+type testCar struct {
+	ID                uint     `json:"ID"`
+	UserID            uint     `json:"user_id"`
+	OrganizationID    *uint    `json:"organization_id"`
+	Title             string   `json:"title"`
+	Description       string   `json:"description"`
+	Tags              []string `json:"tags"`
+	Images            []string `json:"images"`
+	Make              string   `json:"make"`
+	ModelName         string   `json:"model"`
+	VIN               string   `json:"vin"`
+	RegistrationPlate string   `json:"registration_plate"`
+	PublicSlug        *string  `json:"public_slug"`
+	Version           int      `json:"version"`
+}
+
+// carPage is a page of cars or search results
+type carPage struct {
+	Data       []testCar `json:"data"`
+	Pagination struct {
+		Page       int   `json:"page"`
+		PageSize   int   `json:"page_size"`
+		Total      int64 `json:"total"`
+		TotalPages int   `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+// createCar creates a car with the given form fields and returns it
+func (s *testServer) createCar(user testUser, fields map[string]string) testCar {
+	s.t.Helper()
+	var car testCar
+	s.expect(request{method: "POST", path: "/api/cars", token: user.token, body: &multipartForm{fields: fields}}, http.StatusCreated, &car)
+	return car
+}
+
+func carPath(id uint, rest ...string) string {
+	return fmt.Sprintf("/api/cars/%d", id) + strings.Join(rest, "")
+}
+
+func carTitles(cars []testCar) []string {
+	var titles []string
+	for _, car := range cars {
+		titles = append(titles, car.Title)
+	}
+	return titles
+}
+
+// errorCode returns the machine-readable code of an error response
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var out struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decoding error %s: %v", w.Body, err)
+	}
+	return out.Error.Code
+}
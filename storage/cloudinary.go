@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+// versionSegment matches the optional "v1699999999/" prefix Cloudinary puts
+// in front of the public ID in delivery URLs
+var versionSegment = regexp.MustCompile(`^v\d+/`)
+
+// CloudinaryStore keeps images in a Cloudinary folder. Keys are Cloudinary
+// public IDs.
+type CloudinaryStore struct {
+	cld       *cloudinary.Cloudinary
+	cloudName string
+	folder    string
+}
+
+func NewCloudinaryStore(cld *cloudinary.Cloudinary, cloudName, folder string) *CloudinaryStore {
+	return &CloudinaryStore{cld: cld, cloudName: cloudName, folder: folder}
+}
+
+func (s *CloudinaryStore) Name() string {
+	return "cloudinary"
+}
+
+func (s *CloudinaryStore) Put(ctx context.Context, filename string, r io.Reader) (string, error) {
+	result, err := s.cld.Upload.Upload(ctx, r, uploader.UploadParams{Folder: s.folder})
+	if err != nil {
+		return "", err
+	}
+	if result.Error.Message != "" {
+		return "", errors.New(result.Error.Message)
+	}
+	return result.PublicID, nil
+}
+
+func (s *CloudinaryStore) Delete(ctx context.Context, key string) error {
+	result, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: key})
+	if err != nil {
+		return err
+	}
+	if result.Error.Message != "" {
+		return errors.New(result.Error.Message)
+	}
+	return nil
+}
+
+// Ping checks that Cloudinary is reachable with the configured credentials
+func (s *CloudinaryStore) Ping(ctx context.Context) error {
+	result, err := s.cld.Admin.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	if result.Error.Message != "" {
+		return errors.New(result.Error.Message)
+	}
+	return nil
+}
+
+func (s *CloudinaryStore) PublicURL(key string) string {
+	return s.baseURL() + key
+}
+
+func (s *CloudinaryStore) KeyFromURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	u.Scheme = "https"
+
+	rest, ok := strings.CutPrefix(u.String(), s.baseURL())
+	if !ok || rest == "" {
+		return "", false
+	}
+
+	// Older records store the upload's secure_url, which carries a version
+	// and file extension that are not part of the public ID
+	rest = versionSegment.ReplaceAllString(rest, "")
+	return strings.TrimSuffix(rest, path.Ext(rest)), true
+}
+
+func (s *CloudinaryStore) baseURL() string {
+	return "https://res.cloudinary.com/" + s.cloudName + "/image/upload/"
+}
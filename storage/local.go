@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/akashkumar7902/car-management-backend/utils"
+)
+
+var safeExtension = regexp.MustCompile(`^\.[a-z0-9]{1,8}$`)
+
+// LocalStore keeps images on the local filesystem, served by the /uploads
+// static route. Keys are flat file names inside Dir.
+type LocalStore struct {
+	Dir     string
+	BaseURL string
+}
+
+func NewLocalStore(dir, baseURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{Dir: dir, BaseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (s *LocalStore) Name() string {
+	return "local"
+}
+
+func (s *LocalStore) Put(ctx context.Context, filename string, r io.Reader) (string, error) {
+	name, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	if ext := strings.ToLower(filepath.Ext(filename)); safeExtension.MatchString(ext) {
+		name += ext
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.Dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return name, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	if key == "" || key != filepath.Base(key) {
+		return errors.New("invalid image key")
+	}
+	err := os.Remove(filepath.Join(s.Dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Ping checks that Dir exists and is writable
+func (s *LocalStore) Ping(ctx context.Context) error {
+	f, err := os.CreateTemp(s.Dir, ".ping-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+func (s *LocalStore) PublicURL(key string) string {
+	return s.BaseURL + "/" + key
+}
+
+func (s *LocalStore) KeyFromURL(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.BaseURL+"/")
+	if !ok || key == "" || key != filepath.Base(key) {
+		return "", false
+	}
+	return key, true
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/akashkumar7902/car-management-backend/config"
+	"github.com/cloudinary/cloudinary-go/v2"
+)
+
+// ImageStore persists uploaded car images. Stores hand out opaque keys;
+// callers keep the public URL and can map it back to a key for deletion.
+type ImageStore interface {
+	// Name identifies the backend, e.g. "cloudinary" or "local"
+	Name() string
+	// Put stores the image read from r and returns its key. filename is
+	// the client-supplied name and is only used as a hint for the extension.
+	Put(ctx context.Context, filename string, r io.Reader) (string, error)
+	// Delete removes the image. Deleting a missing image is not an error.
+	Delete(ctx context.Context, key string) error
+	// PublicURL returns the URL clients use to fetch the image
+	PublicURL(key string) string
+	// KeyFromURL resolves a URL previously returned by PublicURL back to its
+	// key. It returns false for URLs this store does not own.
+	KeyFromURL(url string) (string, bool)
+	// Ping checks that the backend is reachable and accepting images
+	Ping(ctx context.Context) error
+}
+
+// New returns the image store selected by STORAGE_DRIVER
+func New(cfg config.Config) (ImageStore, error) {
+	switch cfg.StorageDriver {
+	case "", "cloudinary":
+		cld, err := cloudinary.NewFromParams(cfg.CloudName, cfg.CloudAPIKey, cfg.CloudAPISecret)
+		if err != nil {
+			return nil, err
+		}
+		return NewCloudinaryStore(cld, cfg.CloudName, "car_management"), nil
+	case "local":
+		return NewLocalStore(cfg.UploadDir, cfg.UploadBaseURL)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
+
+// DeleteURLs removes the images behind the given public URLs, skipping URLs
+// the store does not own. Every URL is attempted; the failures are returned
+// together.
+func DeleteURLs(ctx context.Context, store ImageStore, urls []string) error {
+	var errs []error
+	for _, url := range urls {
+		key, ok := store.KeyFromURL(url)
+		if !ok {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("deleting image %s: %w", url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -6,17 +6,31 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
+// TokenPurposeTwoFactor marks a challenge token issued after a correct
+// password for an account with two-factor authentication enabled. It only
+// proves the first factor and must not be accepted as an access token.
+const TokenPurposeTwoFactor = "2fa_challenge"
+
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID    uint   `json:"user_id"`
+	SessionID uint   `json:"sid,omitempty"`
+	Purpose   string `json:"purpose,omitempty"`
+	// OrganizationID is the organization the token acts in, 0 for none
+	OrganizationID uint `json:"org,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateToken(userID uint, secret string) (string, error) {
-	expirationTime := time.Now().Add(30 * 24 * time.Hour) // 30 days
+// GenerateToken issues a short-lived access token bound to a session and
+// acting in the given organization (0 for none)
+func GenerateToken(userID, sessionID, organizationID uint, secret string, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
+		UserID:         userID,
+		SessionID:      sessionID,
+		OrganizationID: organizationID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ttl).Unix(),
 		},
 	}
 
@@ -29,10 +43,29 @@ func GenerateToken(userID uint, secret string) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateChallengeToken issues a short-lived token for the second step of a
+// two-factor login
+func GenerateChallengeToken(userID uint, secret string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:  userID,
+		Purpose: TokenPurposeTwoFactor,
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ttl).Unix(),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
 func ValidateToken(tokenStr string, secret string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
 		return []byte(secret), nil
 	})
 
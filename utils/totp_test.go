@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 key of the RFC 6238 test vectors,
+// "12345678901234567890", in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last six digits of the RFC 6238 SHA-1 vectors
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.code {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	// "287082" is the code of time step 1, which covers 30s to 59s
+	tests := []struct {
+		name        string
+		secret      string
+		code        string
+		unix        int64
+		lastCounter int64
+		counter     int64
+		ok          bool
+	}{
+		{"current step", rfc6238Secret, "287082", 59, 0, 1, true},
+		{"spaces and lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "287 082", 45, 0, 1, true},
+		{"one step late", rfc6238Secret, "287082", 89, 0, 1, true},
+		{"one step early", rfc6238Secret, "287082", 0, 0, 1, true},
+		{"two steps late", rfc6238Secret, "287082", 90, 0, 0, false},
+		{"replayed", rfc6238Secret, "287082", 59, 1, 0, false},
+		{"older than last use", rfc6238Secret, "287082", 89, 2, 0, false},
+		{"wrong code", rfc6238Secret, "287083", 59, 0, 0, false},
+		{"too short", rfc6238Secret, "28708", 59, 0, 0, false},
+		{"invalid secret", "not base32!", "287082", 59, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, ok := ValidateTOTP(tt.secret, tt.code, time.Unix(tt.unix, 0), tt.lastCounter)
+			if counter != tt.counter || ok != tt.ok {
+				t.Errorf("ValidateTOTP = %d, %v, want %d, %v", counter, ok, tt.counter, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"abcde-fghij", "abcde-fghij"},
+		{" ABCDE-FGHIJ ", "abcde-fghij"},
+		{"abcdefghij", "abcde-fghij"},
+		{"abcde fghij", "abcde-fghij"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRecoveryCode(tt.in); got != tt.want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
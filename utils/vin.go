@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+	ErrVINLength     = errors.New("VIN must be 17 characters")
+	ErrVINCharacters = errors.New("VIN may only contain digits and letters other than I, O and Q")
+	ErrVINCheckDigit = errors.New("VIN check digit is invalid")
+)
+
+// NormalizeVIN upper-cases a VIN and strips surrounding whitespace
+func NormalizeVIN(vin string) string {
+	return strings.ToUpper(strings.TrimSpace(vin))
+}
+
+// ValidateVIN checks the length and alphabet of a normalized VIN, and the
+// check digit (position 9) of North American VINs. ISO 3779 leaves position
+// 9 to the manufacturer; only the US and Canadian rules (49 CFR 565) make it
+// a check digit, so it is enforced for WMIs starting with 1 to 5.
+func ValidateVIN(vin string) error {
+	if len(vin) != 17 {
+		return ErrVINLength
+	}
+
+	sum := 0
+	for i := 0; i < len(vin); i++ {
+		value, ok := vinValue(vin[i])
+		if !ok {
+			return ErrVINCharacters
+		}
+		sum += value * vinWeights[i]
+	}
+
+	if vin[0] < '1' || vin[0] > '5' {
+		return nil
+	}
+	check := byte('0' + sum%11)
+	if sum%11 == 10 {
+		check = 'X'
+	}
+	if vin[8] != check {
+		return ErrVINCheckDigit
+	}
+	return nil
+}
+
+// vinValue transliterates a VIN character to its numeric value
+func vinValue(ch byte) (int, bool) {
+	switch {
+	case ch >= '0' && ch <= '9':
+		return int(ch - '0'), true
+	case ch >= 'A' && ch <= 'H':
+		return int(ch-'A') + 1, true
+	case ch >= 'J' && ch <= 'N':
+		return int(ch-'J') + 1, true
+	case ch == 'P':
+		return 7, true
+	case ch == 'R':
+		return 9, true
+	case ch >= 'S' && ch <= 'Z':
+		return int(ch-'S') + 2, true
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateVIN(t *testing.T) {
+	tests := []struct {
+		name string
+		vin  string
+		err  error
+	}{
+		{"check digit X", "1M8GDM9AXKP042788", nil},
+		{"numeric check digit", "1HGCM82633A004352", nil},
+		{"all letter ranges", "JH4KA7561PC008269", nil},
+		{"all digits", "11111111111111111", nil},
+		{"wrong check digit", "1M8GDM9A1KP042788", ErrVINCheckDigit},
+		{"wrong check digit from Canada", "2HGCM82633A004352", ErrVINCheckDigit},
+		{"European VIN without a check digit", "WVWZZZ1JZXW000001", nil},
+		{"Japanese VIN with another check digit", "JH4KA7561PC008260", nil},
+		{"European VIN with a bad character", "WVWZZZ1JZXW00000I", ErrVINCharacters},
+		{"too short", "1M8GDM9AXKP04278", ErrVINLength},
+		{"too long", "1M8GDM9AXKP0427888", ErrVINLength},
+		{"empty", "", ErrVINLength},
+		{"letter I", "1M8GDM9AXKP04278I", ErrVINCharacters},
+		{"letter O", "1M8GDM9AXKP04278O", ErrVINCharacters},
+		{"letter Q", "1M8GDM9AXKP04278Q", ErrVINCharacters},
+		{"lowercase", "1m8gdm9axkp042788", ErrVINCharacters},
+		{"punctuation", "1M8GDM9AXKP04278-", ErrVINCharacters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateVIN(tt.vin); !errors.Is(err, tt.err) {
+				t.Errorf("ValidateVIN(%q) = %v, want %v", tt.vin, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestVINValue(t *testing.T) {
+	tests := []struct {
+		ch    byte
+		value int
+		ok    bool
+	}{
+		{'0', 0, true},
+		{'9', 9, true},
+		{'A', 1, true},
+		{'H', 8, true},
+		{'J', 1, true},
+		{'N', 5, true},
+		{'P', 7, true},
+		{'R', 9, true},
+		{'S', 2, true},
+		{'Z', 9, true},
+		{'I', 0, false},
+		{'O', 0, false},
+		{'Q', 0, false},
+		{'a', 0, false},
+	}
+	for _, tt := range tests {
+		value, ok := vinValue(tt.ch)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("vinValue(%q) = %d, %v, want %d, %v", tt.ch, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestNormalizeVIN(t *testing.T) {
+	if got := NormalizeVIN("  1m8gdm9axkp042788\n"); got != "1M8GDM9AXKP042788" {
+		t.Errorf("NormalizeVIN = %q", got)
+	}
+}